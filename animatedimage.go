@@ -0,0 +1,209 @@
+package tg
+
+import (
+	"image"
+	"image/draw"
+	"image/gif"
+	"io"
+	"io/fs"
+	"strconv"
+)
+
+// ======== AnimatedImage =================
+type AnimatedImage struct {
+	Image
+	frames  []*image.NRGBA // whole frames after disposal of previous ones
+	delays  []int          // delays of frames in milliseconds
+	photos  []string       // uploaded frames
+	loops   int            // count of plays, 0 for endless playing
+	played  int
+	current int
+	playing bool
+	timer   string // id of "after" for next frame
+	tick    string // command showing next frame
+}
+
+// Return pointer to new Image widget playing GIF animation from "r".
+// Animation starts when widget is created and is repeated as it is set in GIF.
+func NewAnimatedImage(r io.Reader, flags uint) (*AnimatedImage, error) {
+	g, err := gif.DecodeAll(r)
+	if err != nil {
+		return nil, err
+	}
+	a := AnimatedImage{Image: *NewImage("", flags), playing: true}
+	a.frames = composeGIF(g)
+	for _, d := range g.Delay {
+		// browsers show frames with too small delay for 100 ms too
+		if d < 2 {
+			d = 10
+		}
+		a.delays = append(a.delays, d*10)
+	}
+	switch {
+	case g.LoopCount < 0:
+		a.loops = 1
+	case g.LoopCount > 0:
+		a.loops = g.LoopCount + 1
+	}
+	return &a, nil
+}
+
+// Return pointer to new Image widget playing GIF animation "path" from "fsys" (f.e. embed.FS).
+func NewAnimatedImageFromFS(fsys fs.FS, path string, flags uint) (*AnimatedImage, error) {
+	f, err := fsys.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return NewAnimatedImage(f, flags)
+}
+
+// Return whole frames of GIF with disposal methods applied.
+func composeGIF(g *gif.GIF) []*image.NRGBA {
+	bounds := image.Rect(0, 0, g.Config.Width, g.Config.Height)
+	for _, f := range g.Image {
+		bounds = bounds.Union(f.Bounds())
+	}
+	canvas := image.NewNRGBA(bounds)
+	frames := []*image.NRGBA{}
+	for i, f := range g.Image {
+		disposal := byte(0)
+		if i < len(g.Disposal) {
+			disposal = g.Disposal[i]
+		}
+		var prev *image.NRGBA
+		if disposal == gif.DisposalPrevious {
+			prev = cloneNRGBA(canvas)
+		}
+		draw.Draw(canvas, f.Bounds(), f, f.Bounds().Min, draw.Over)
+		frames = append(frames, cloneNRGBA(canvas))
+		switch disposal {
+		case gif.DisposalBackground:
+			draw.Draw(canvas, f.Bounds(), image.Transparent, image.Point{}, draw.Src)
+		case gif.DisposalPrevious:
+			canvas = prev
+		}
+	}
+	return frames
+}
+
+func cloneNRGBA(img *image.NRGBA) *image.NRGBA {
+	res := image.NewNRGBA(img.Bounds())
+	copy(res.Pix, img.Pix)
+	return res
+}
+
+func (a *AnimatedImage) create(parentId string) (string, uint) {
+	id, flags := a.Image.create(parentId)
+	widgets[id] = a
+	a.tick = addCallbackCmd(func(s string) {
+		a.timer = ""
+		a.next()
+	})
+	eval("bind " + id + " <Destroy> {+" + addCallbackCmd(func(s string) {
+		a.cancel()
+		for _, p := range a.photos {
+			if p != "" {
+				eval("image delete " + p)
+			}
+		}
+		a.photos = nil
+	}) + "}")
+	a.photos = make([]string, len(a.frames))
+	a.show()
+	if a.playing {
+		a.schedule()
+	}
+	return id, flags
+}
+
+// Set count of plays of animation, 0 for endless playing.
+func (a *AnimatedImage) SetLoopCount(n int) {
+	a.loops = n
+}
+
+func (a *AnimatedImage) FrameCount() int {
+	return len(a.frames)
+}
+
+// Continue playing of animation, animation is started again if it is finished.
+func (a *AnimatedImage) Play() {
+	if a.playing {
+		return
+	}
+	if a.loops > 0 && a.played >= a.loops {
+		a.played = 0
+		a.current = 0
+		a.show()
+	}
+	a.playing = true
+	a.schedule()
+}
+
+// Stop animation at current frame.
+func (a *AnimatedImage) Pause() {
+	a.playing = false
+	a.cancel()
+}
+
+// Stop animation and show first frame.
+func (a *AnimatedImage) Stop() {
+	a.Pause()
+	a.current = 0
+	a.played = 0
+	a.show()
+}
+
+func (a *AnimatedImage) Playing() bool {
+	return a.playing
+}
+
+func (a *AnimatedImage) schedule() {
+	if a.id == "" || len(a.frames) < 2 || a.timer != "" {
+		return
+	}
+	eval("after " + strconv.Itoa(a.delays[a.current]) + " " + a.tick)
+	a.timer = result()
+}
+
+func (a *AnimatedImage) cancel() {
+	if a.timer != "" {
+		eval("after cancel " + a.timer)
+		a.timer = ""
+	}
+}
+
+func (a *AnimatedImage) next() {
+	if !a.playing {
+		return
+	}
+	a.current++
+	if a.current >= len(a.frames) {
+		a.played++
+		if a.loops > 0 && a.played >= a.loops {
+			// last frame stays shown
+			a.current = len(a.frames) - 1
+			a.playing = false
+			return
+		}
+		a.current = 0
+	}
+	a.show()
+	a.schedule()
+}
+
+// Show current frame, frames are uploaded when they are shown first time.
+func (a *AnimatedImage) show() {
+	if a.id == "" || len(a.frames) == 0 {
+		return
+	}
+	if a.photos[a.current] == "" {
+		name := "tganim" + genNextId()
+		if err := Upload_image(name, a.frames[a.current]); err != nil {
+			return
+		}
+		a.photos[a.current] = name
+	}
+	a.imageFile = a.photos[a.current]
+	eval(a.id + " configure -image " + a.imageFile)
+}
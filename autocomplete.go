@@ -0,0 +1,292 @@
+package tg
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const (
+	acSelTag   = "tgacsel"
+	acMatchTag = "tgacmatch"
+)
+
+// ======== AutocompleteEntry =================
+type AutocompleteEntry struct {
+	Entry
+	provider func(prefix string) []string
+	async    bool
+	delay    int
+	minChars int
+	maxRows  int
+	popup    string
+	list     string
+	items    []string
+	current  int
+	seq      int    // number of last request, results of older requests are ignored
+	debounce string // command requesting suggestions after delay
+	timer    string // id of "after" for debounce command
+	onSelect func(item string)
+}
+
+// Return pointer to new Entry which shows suggestions returned by "provider" for typed text.
+func NewAutocompleteEntry(text string, provider func(prefix string) []string, flags uint) *AutocompleteEntry {
+	e := NewEntry(text, flags)
+	return &AutocompleteEntry{Entry: *e, provider: provider, delay: 200, minChars: 1, maxRows: 8, current: -1}
+}
+
+func (a *AutocompleteEntry) create(parentId string) (string, uint) {
+	id, flags := a.Entry.create(parentId)
+	widgets[id] = a
+	a.popup = ".tgac" + genNextId()
+	a.list = a.popup + ".list"
+	eval("toplevel " + a.popup + " -borderwidth 1 -background gray50")
+	eval("wm overrideredirect " + a.popup + " 1")
+	eval("wm withdraw " + a.popup)
+	eval("text " + a.list + " -wrap none -cursor arrow -borderwidth 0 -highlightthickness 0 -state disabled")
+	eval("pack " + a.list + " -fill both -expand yes")
+	eval(a.list + " tag configure " + acSelTag + " -background #4a6984 -foreground white")
+	eval(a.list + " tag configure " + acMatchTag + " -font [concat [font actual [" + a.list + " cget -font]] -weight bold]")
+	eval(a.list + " tag raise " + acSelTag)
+
+	// own bind tag before tag of widget allows to break Entry bindings for navigation keys
+	tag := "tgac" + genNextId()
+	eval("bindtags " + id + " [linsert [bindtags " + id + "] 0 " + tag + "]")
+	a.debounce = addCallbackCmd(func(s string) {
+		a.timer = ""
+		a.request(a.GetText())
+	})
+	eval("bind " + tag + " <KeyPress> {" + addCallbackCmd(a.key) + " %K; if {$tgbreak} break}")
+	eval("bind " + tag + " <FocusOut> {after 150 " + addCallbackCmd(func(s string) {
+		eval("focus")
+		if result() != a.id {
+			a.Hide()
+		}
+	}) + "}")
+	eval("bind " + id + " <Destroy> {+" + addCallbackCmd(func(s string) {
+		a.cancel()
+		eval("destroy " + a.popup)
+	}) + "}")
+	eval("bind " + a.list + " <Motion> {" + addCallbackCmd(func(s string) {
+		p := splitList(s)
+		if len(p) > 1 {
+			a.choose(a.row(p[1]))
+		}
+	}) + " @%x,%y}")
+	eval("bind " + a.list + " <ButtonRelease-1> {" + addCallbackCmd(func(s string) {
+		p := splitList(s)
+		if len(p) > 1 {
+			a.choose(a.row(p[1]))
+			a.accept()
+		}
+	}) + " @%x,%y}")
+	SetVar("tgbreak", "0")
+	return id, flags
+}
+
+// Call provider in goroutine, results are shown in main loop.
+func (a *AutocompleteEntry) SetAsync(async bool) {
+	a.async = async
+}
+
+// Set delay in milliseconds after last typed char before provider is called (200 by default).
+func (a *AutocompleteEntry) SetDelay(ms int) {
+	a.delay = ms
+}
+
+// Set minimal length of text for suggestions (1 by default).
+func (a *AutocompleteEntry) SetMinChars(n int) {
+	a.minChars = n
+}
+
+// Set maximal count of visible suggestions (8 by default).
+func (a *AutocompleteEntry) SetMaxRows(n int) {
+	a.maxRows = n
+}
+
+// Call "f" when suggestion is accepted by Enter or mouse click.
+func (a *AutocompleteEntry) OnSelect(f func(item string)) {
+	a.onSelect = f
+}
+
+// Hide suggestions.
+func (a *AutocompleteEntry) Hide() {
+	a.seq++
+	a.cancel()
+	a.items = nil
+	a.current = -1
+	eval("wm withdraw " + a.popup)
+}
+
+func (a *AutocompleteEntry) visible() bool {
+	eval("winfo ismapped " + a.popup)
+	return result() == "1"
+}
+
+func (a *AutocompleteEntry) key(s string) {
+	SetVar("tgbreak", "0")
+	p := splitList(s)
+	if len(p) < 2 {
+		return
+	}
+	shown := a.visible() && len(a.items) > 0
+	switch p[1] {
+	case "Down", "Up":
+		if !shown {
+			if p[1] == "Down" {
+				a.request(a.GetText())
+			}
+			return
+		}
+		n := a.current + 1
+		if p[1] == "Up" {
+			n = a.current - 1
+		}
+		if n < 0 {
+			n = len(a.items) - 1
+		}
+		a.choose(n % len(a.items))
+	case "Return", "KP_Enter", "Tab":
+		if !shown || a.current < 0 {
+			a.Hide()
+			return
+		}
+		a.accept()
+	case "Escape":
+		if !shown {
+			return
+		}
+		a.Hide()
+	case "Left", "Right", "Home", "End", "Shift_L", "Shift_R", "Control_L", "Control_R",
+		"Alt_L", "Alt_R", "Prior", "Next":
+		return
+	default:
+		// text is changed after this binding, so read it after Entry bindings and delay
+		a.seq++
+		a.cancel()
+		eval("after " + strconv.Itoa(a.delay) + " " + a.debounce)
+		a.timer = result()
+		return
+	}
+	SetVar("tgbreak", "1")
+}
+
+// Cancel pending request of suggestions.
+func (a *AutocompleteEntry) cancel() {
+	if a.timer != "" {
+		eval("after cancel " + a.timer)
+		a.timer = ""
+	}
+}
+
+// Call provider for "prefix" and show results.
+func (a *AutocompleteEntry) request(prefix string) {
+	if a.provider == nil || len([]rune(prefix)) < a.minChars {
+		a.Hide()
+		return
+	}
+	a.seq++
+	seq := a.seq
+	if !a.async {
+		a.show(prefix, a.provider(prefix))
+		return
+	}
+	go func() {
+		items := a.provider(prefix)
+		Async(func() {
+			if seq == a.seq && a.GetText() == prefix {
+				a.show(prefix, items)
+			}
+		})
+	}()
+}
+
+// Show "items" under Entry with highlighted "prefix".
+func (a *AutocompleteEntry) show(prefix string, items []string) {
+	if len(items) == 0 {
+		a.Hide()
+		return
+	}
+	a.items = items
+	a.current = -1
+	var sb strings.Builder
+	for i, it := range items {
+		r := []rune(it)
+		from, length := matchIndex(r, []rune(prefix))
+		if i > 0 {
+			sb.WriteString(" " + tkstr("\n") + " {}")
+		}
+		if from < 0 {
+			sb.WriteString(" " + tkstr(it) + " {}")
+			continue
+		}
+		sb.WriteString(" " + tkstr(string(r[:from])) + " {}")
+		sb.WriteString(" " + tkstr(string(r[from:from+length])) + " " + acMatchTag)
+		sb.WriteString(" " + tkstr(string(r[from+length:])) + " {}")
+	}
+	rows := len(items)
+	if rows > a.maxRows {
+		rows = a.maxRows
+	}
+	eval(a.list + " configure -state normal")
+	eval(a.list + " delete 1.0 end")
+	eval(a.list + " insert end" + sb.String())
+	eval(a.list + " configure -state disabled -height " + strconv.Itoa(rows))
+	eval(a.list + " yview moveto 0")
+	eval("wm geometry " + a.popup + " [winfo width " + a.id + "]x[expr {[winfo reqheight " + a.list + "] + 2}]" +
+		"+[winfo rootx " + a.id + "]+[expr {[winfo rooty " + a.id + "] + [winfo height " + a.id + "]}]")
+	eval("wm deiconify " + a.popup)
+	eval("raise " + a.popup)
+}
+
+// Return position and length in runes of first case insensitive match of "sub" in "s", -1 if there is no match.
+func matchIndex(s []rune, sub []rune) (int, int) {
+	if len(sub) == 0 {
+		return -1, 0
+	}
+	for i := 0; i+len(sub) <= len(s); i++ {
+		ok := true
+		for j, r := range sub {
+			if unicode.ToLower(s[i+j]) != unicode.ToLower(r) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return i, len(sub)
+		}
+	}
+	return -1, 0
+}
+
+// Return number of suggestion at text index "index".
+func (a *AutocompleteEntry) row(index string) int {
+	eval(a.list + " index " + tkstr(index))
+	line, _ := splitIndex(result())
+	return line - 1
+}
+
+func (a *AutocompleteEntry) choose(n int) {
+	if n < 0 || n >= len(a.items) || n == a.current {
+		return
+	}
+	a.current = n
+	line := strconv.Itoa(n + 1)
+	eval(a.list + " tag remove " + acSelTag + " 1.0 end")
+	eval(a.list + " tag add " + acSelTag + " " + line + ".0 {" + line + ".0 lineend +1c}")
+	eval(a.list + " see " + line + ".0")
+}
+
+func (a *AutocompleteEntry) accept() {
+	if a.current < 0 || a.current >= len(a.items) {
+		return
+	}
+	item := a.items[a.current]
+	a.Hide()
+	a.SetText(item)
+	eval(a.id + " icursor end")
+	eval("focus " + a.id)
+	if a.onSelect != nil {
+		a.onSelect(item)
+	}
+}
@@ -0,0 +1,60 @@
+package tg
+
+import (
+	"errors"
+	"time"
+)
+
+// Return selected date (zero time if shown text is wrong).
+func (t *Calendar) Value() time.Time {
+	if t.id == "" {
+		return t.ltime
+	}
+	tm, err := t.opts.parse(t.lb.Text())
+	if err != nil {
+		return time.Time{}
+	}
+	return tm
+}
+
+// Set date, error is returned for date out of range or disabled date.
+func (t *Calendar) SetValue(date time.Time) error {
+	date = dateOnly(date)
+	if !t.Allowed(date) {
+		return errors.New("Date " + date.Format("2006-01-02") + " is not allowed!")
+	}
+	t.ltime = date
+	if t.id != "" {
+		SetVar(t.ltextvariable, date.Format(t.opts.Layout))
+	}
+	return nil
+}
+
+// Call "f" when user accepts date in dialog.
+func (t *Calendar) OnChange(f func(date time.Time)) {
+	t.onChange = f
+}
+
+// Show month "n" months after shown one, day is limited by length of month.
+func (t *Calendar) shiftMonth(n int) {
+	year, month, day := t.ltime.Date()
+	first := time.Date(year, month+time.Month(n), 1, 0, 0, 0, 0, time.Local)
+	last := first.AddDate(0, 1, -1).Day()
+	if day > last {
+		day = last
+	}
+	if t.day > last {
+		t.day = last
+	}
+	t.ltime = time.Date(first.Year(), first.Month(), day, 0, 0, 0, 0, time.Local)
+	t.makeMonth()
+}
+
+// Return date of "tm" at midnight of local time, zero time stays zero.
+func dateOnly(tm time.Time) time.Time {
+	if tm.IsZero() {
+		return tm
+	}
+	y, m, d := tm.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.Local)
+}
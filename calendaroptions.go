@@ -0,0 +1,103 @@
+package tg
+
+import (
+	"errors"
+	"time"
+)
+
+// Texts and layouts of Calendar.
+type CalendarOptions struct {
+	Weekdays     [7]string  // short names of weekdays from Sunday
+	Months       [12]string // names of months from January
+	Title        string     // title of dialog
+	Cancel       string     // labels of buttons
+	Accept       string
+	FirstDay     time.Weekday // first day of week
+	Layout       string       // layout of shown date (see time.Format)
+	ParseLayouts []string     // other layouts accepted by Calendar.Set
+	Presets      [5]string    // names of DateRangePicker presets
+}
+
+var CalendarEn = CalendarOptions{
+	Weekdays: [7]string{"Su", "Mo", "Tu", "We", "Th", "Fr", "Sa"},
+	Months: [12]string{"January", "February", "March", "April", "May", "June",
+		"July", "August", "September", "October", "November", "December"},
+	Title:        "Select date",
+	Cancel:       "Cancel",
+	Accept:       "OK",
+	FirstDay:     time.Sunday,
+	Layout:       "01/02/2006",
+	ParseLayouts: []string{"2006-01-02"},
+	Presets:      [5]string{"Today", "This week", "This month", "Last 30 days", "This quarter"},
+}
+
+var CalendarUk = CalendarOptions{
+	Weekdays: [7]string{"Нд", "Пн", "Вт", "Ср", "Чт", "Пт", "Сб"},
+	Months: [12]string{"Січень", "Лютий", "Березень", "Квітень", "Травень", "Червень",
+		"Липень", "Серпень", "Вересень", "Жовтень", "Листопад", "Грудень"},
+	Title:        "Оберіть дату",
+	Cancel:       "Відмінити",
+	Accept:       "Прийняти",
+	FirstDay:     time.Monday,
+	Layout:       "02.01.2006",
+	ParseLayouts: []string{"2006-01-02"},
+	Presets:      [5]string{"Сьогодні", "Цей тиждень", "Цей місяць", "Останні 30 днів", "Цей квартал"},
+}
+
+var CalendarDe = CalendarOptions{
+	Weekdays: [7]string{"So", "Mo", "Di", "Mi", "Do", "Fr", "Sa"},
+	Months: [12]string{"Januar", "Februar", "März", "April", "Mai", "Juni",
+		"Juli", "August", "September", "Oktober", "November", "Dezember"},
+	Title:        "Datum wählen",
+	Cancel:       "Abbrechen",
+	Accept:       "Übernehmen",
+	FirstDay:     time.Monday,
+	Layout:       "02.01.2006",
+	ParseLayouts: []string{"2006-01-02"},
+	Presets:      [5]string{"Heute", "Diese Woche", "Dieser Monat", "Letzte 30 Tage", "Dieses Quartal"},
+}
+
+// Options of new Calendars.
+var calendarOptions = CalendarUk
+
+// Set options for Calendars created after this call (CalendarUk by default).
+func SetCalendarOptions(o CalendarOptions) {
+	calendarOptions = o
+}
+
+// Return options of Calendar for language code ("en", "uk" or "de").
+func CalendarLocale(lang string) (CalendarOptions, error) {
+	switch lang {
+	case "en":
+		return CalendarEn, nil
+	case "uk":
+		return CalendarUk, nil
+	case "de":
+		return CalendarDe, nil
+	}
+	return CalendarOptions{}, errors.New("Unknown calendar locale " + lang)
+}
+
+// Parse "s" with Layout or one of ParseLayouts.
+func (o *CalendarOptions) parse(s string) (time.Time, error) {
+	tm, err := time.ParseInLocation(o.Layout, s, time.Local)
+	if err == nil {
+		return tm, nil
+	}
+	for _, l := range o.ParseLayouts {
+		if tm, e := time.ParseInLocation(l, s, time.Local); e == nil {
+			return tm, nil
+		}
+	}
+	return time.Time{}, err
+}
+
+// Return weekday shown in column "col" of month.
+func (o *CalendarOptions) weekday(col int) time.Weekday {
+	return time.Weekday((int(o.FirstDay) + col) % 7)
+}
+
+// Return column of weekday "wd" in month.
+func (o *CalendarOptions) column(wd time.Weekday) int {
+	return (int(wd) - int(o.FirstDay) + 7) % 7
+}
@@ -0,0 +1,240 @@
+package tg
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"strings"
+)
+
+// States of CheckTree node
+const (
+	Unchecked = iota
+	Checked
+	PartChecked
+)
+
+var checkImages []string
+
+// Draw images for check box states and upload it to Tk (only once).
+func initCheckImages() {
+	if checkImages != nil {
+		return
+	}
+	const size = 13
+	border := color.NRGBA{0x60, 0x60, 0x60, 0xff}
+	fill := color.NRGBA{0xff, 0xff, 0xff, 0xff}
+	mark := color.NRGBA{0x20, 0x20, 0x20, 0xff}
+
+	for state := Unchecked; state <= PartChecked; state++ {
+		img := image.NewNRGBA(image.Rect(0, 0, size, size))
+		for x := 0; x < size; x++ {
+			for y := 0; y < size; y++ {
+				if x == 0 || y == 0 || x == size-1 || y == size-1 {
+					img.SetNRGBA(x, y, border)
+				} else {
+					img.SetNRGBA(x, y, fill)
+				}
+			}
+		}
+		switch state {
+		case Checked:
+			// short stroke down and long stroke up
+			for i := 0; i < 3; i++ {
+				img.SetNRGBA(3+i, 5+i, mark)
+				img.SetNRGBA(3+i, 6+i, mark)
+			}
+			for i := 0; i < 5; i++ {
+				img.SetNRGBA(6+i, 7-i, mark)
+				img.SetNRGBA(6+i, 8-i, mark)
+			}
+		case PartChecked:
+			for x := 3; x < size-3; x++ {
+				for y := 3; y < size-3; y++ {
+					img.SetNRGBA(x, y, mark)
+				}
+			}
+		}
+		name := "tgcheck" + genNextId()
+		Upload_image(name, img)
+		checkImages = append(checkImages, name)
+	}
+}
+
+// ======== CheckTree =================
+type CheckTree struct {
+	Tree
+	states   map[string]int
+	onChange func(id string, checked bool)
+}
+
+// Return pointer to new Tree with tri-state check box for every node.
+// Checking node checks all its children, parent becomes partially checked
+// if only some of its children are checked.
+func NewCheckTree(flags uint) *CheckTree {
+	t := NewTree(flags)
+	return &CheckTree{*t, map[string]int{}, nil}
+}
+
+func (t *CheckTree) create(parentId string) (string, uint) {
+	id, flags := t.Tree.create(parentId)
+	widgets[id] = t
+	initCheckImages()
+	t.Bind("<Button-1>", "xy", t.click)
+	t.Bind("<Key-space>", "", func(s string) {
+		sel, _ := t.GetSelection()
+		if sel != "" {
+			t.toggle(sel)
+		}
+	})
+	return id, flags
+}
+
+func (t *CheckTree) click(s string) {
+	p := strings.Split(s, " ")
+	if len(p) < 3 {
+		return
+	}
+	eval(t.id + " identify element " + p[1] + " " + p[2])
+	if !strings.Contains(result(), "image") {
+		return
+	}
+	eval(t.id + " identify item " + p[1] + " " + p[2])
+	if id := result(); id != "" {
+		t.toggle(id)
+	}
+}
+
+func (t *CheckTree) toggle(id string) {
+	checked := t.states[id] != Checked
+	t.SetChecked(id, checked)
+	if t.onChange != nil {
+		t.onChange(id, checked)
+	}
+}
+
+// Insert node with check box, new node is checked if its parent is checked.
+func (t *CheckTree) Insert(parent string, id string, item string, data []string) {
+	parent = treeId(parent)
+	t.Tree.Insert(parent, id, item, data)
+	t.initNode(parent, id)
+}
+
+// Insert not open node with check box.
+func (t *CheckTree) InsertNO(parent string, id string, item string, data []string) {
+	parent = treeId(parent)
+	t.Tree.InsertNO(parent, id, item, data)
+	t.initNode(parent, id)
+}
+
+func (t *CheckTree) initNode(parent string, id string) {
+	state := Unchecked
+	if parent != "" && t.states[parent] == Checked {
+		state = Checked
+	}
+	t.setState(id, state)
+	t.updateParents(parent)
+}
+
+func (t *CheckTree) Delete(id string) {
+	parent := t.Parent(id)
+	t.forget(id)
+	t.Tree.Delete(id)
+	t.updateParents(parent)
+}
+
+func (t *CheckTree) Move(id string, newParent string, index int) error {
+	newParent = treeId(newParent)
+	oldParent := t.Parent(id)
+	if err := t.Tree.Move(id, newParent, index); err != nil {
+		return err
+	}
+	t.updateParents(oldParent)
+	t.updateParents(newParent)
+	return nil
+}
+
+func (t *CheckTree) Clear() {
+	t.Tree.Clear()
+	t.states = map[string]int{}
+}
+
+// Return Unchecked, Checked or PartChecked.
+func (t *CheckTree) State(id string) int {
+	return t.states[id]
+}
+
+// Check or uncheck node "id" with all its children.
+func (t *CheckTree) SetChecked(id string, checked bool) {
+	state := Unchecked
+	if checked {
+		state = Checked
+	}
+	t.setState(id, state)
+	t.walk(id, func(c string) bool {
+		t.setState(c, state)
+		return false
+	})
+	t.updateParents(t.Parent(id))
+}
+
+// Return ids of all checked nodes (partially checked are not included).
+func (t *CheckTree) GetChecked() []string {
+	res := []string{}
+	t.walk("", func(id string) bool {
+		if t.states[id] == Checked {
+			res = append(res, id)
+		}
+		return false
+	})
+	return res
+}
+
+// Call "f" when user checks or unchecks node.
+func (t *CheckTree) OnCheckChanged(f func(id string, checked bool)) {
+	t.onChange = f
+}
+
+// Image of CheckTree node is its check box, so icons can't be shown and error is returned.
+func (t *CheckTree) SetIcon(id string, icon string) error {
+	return errors.New("CheckTree can't show icon of node " + id + "!")
+}
+
+func (t *CheckTree) setState(id string, state int) {
+	t.states[id] = state
+	eval(t.id + " item " + tkstr(id) + " -image " + checkImages[state])
+}
+
+func (t *CheckTree) forget(id string) {
+	delete(t.states, id)
+	t.walk(id, func(c string) bool {
+		delete(t.states, c)
+		return false
+	})
+}
+
+// Recalculate states of node "id" and its ancestors from their children.
+func (t *CheckTree) updateParents(id string) {
+	for id = treeId(id); id != ""; id = t.Parent(id) {
+		children := t.Children(id)
+		if len(children) == 0 {
+			return
+		}
+		checked, unchecked := 0, 0
+		for _, c := range children {
+			switch t.states[c] {
+			case Checked:
+				checked++
+			case Unchecked:
+				unchecked++
+			}
+		}
+		state := PartChecked
+		if checked == len(children) {
+			state = Checked
+		} else if unchecked == len(children) {
+			state = Unchecked
+		}
+		t.setState(id, state)
+	}
+}
@@ -0,0 +1,412 @@
+package tg
+
+import (
+	"go/scanner"
+	"go/token"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Kinds of tokens for syntax highlighting
+const (
+	TokenKeyword = "keyword"
+	TokenBuiltin = "builtin"
+	TokenString  = "string"
+	TokenNumber  = "number"
+	TokenComment = "comment"
+)
+
+// Highlighted part of source code.
+type Token struct {
+	Offset int    // offset in bytes from the begin of source
+	Length int    // length in bytes
+	Kind   string // TokenKeyword, TokenString, ... or any kind with style set by CodeEditor.SetStyle
+}
+
+// Lexer splits source code into tokens for CodeEditor, tokens must be sorted by Offset
+// and must not overlap.
+type Lexer interface {
+	Tokens(src string) []Token
+}
+
+// ======== GoLexer =================
+
+// Lexer for Go source code based on go/scanner.
+type GoLexer struct{}
+
+var goBuiltins = map[string]bool{}
+
+func init() {
+	for _, s := range strings.Fields(`bool byte complex64 complex128 error float32 float64
+		int int8 int16 int32 int64 rune string uint uint8 uint16 uint32 uint64 uintptr any
+		true false iota nil append cap close complex copy delete imag len make new panic
+		print println real recover`) {
+		goBuiltins[s] = true
+	}
+}
+
+func (GoLexer) Tokens(src string) []Token {
+	res := []Token{}
+	fset := token.NewFileSet()
+	file := fset.AddFile("", fset.Base(), len(src))
+	var s scanner.Scanner
+	// errors are ignored, broken code is highlighted as far as possible
+	s.Init(file, []byte(src), nil, scanner.ScanComments)
+	for {
+		pos, tok, lit := s.Scan()
+		if tok == token.EOF {
+			break
+		}
+		kind := ""
+		length := len(lit)
+		switch {
+		case tok.IsKeyword():
+			kind = TokenKeyword
+			length = len(tok.String())
+		case tok == token.IDENT && goBuiltins[lit]:
+			kind = TokenBuiltin
+		case tok == token.STRING || tok == token.CHAR:
+			kind = TokenString
+		case tok == token.INT || tok == token.FLOAT || tok == token.IMAG:
+			kind = TokenNumber
+		case tok == token.COMMENT:
+			kind = TokenComment
+		}
+		if kind != "" {
+			res = append(res, Token{file.Offset(pos), length, kind})
+		}
+	}
+	return res
+}
+
+// ======== SimpleLexer =================
+
+// Lexer for C-like languages with keywords, comments, strings and numbers.
+type SimpleLexer struct {
+	Keywords     []string
+	LineComment  string    // f.e. "//" or "#"
+	BlockComment [2]string // f.e. {"/*", "*/"}
+	Quotes       string    // string delimiters, f.e. "\"'"
+}
+
+func (l SimpleLexer) Tokens(src string) []Token {
+	keywords := map[string]bool{}
+	for _, k := range l.Keywords {
+		keywords[k] = true
+	}
+	res := []Token{}
+	for i := 0; i < len(src); {
+		r, size := utf8.DecodeRuneInString(src[i:])
+		switch {
+		case l.LineComment != "" && strings.HasPrefix(src[i:], l.LineComment):
+			end := strings.IndexByte(src[i:], '\n')
+			if end < 0 {
+				end = len(src) - i
+			}
+			res = append(res, Token{i, end, TokenComment})
+			i += end
+		case l.BlockComment[0] != "" && strings.HasPrefix(src[i:], l.BlockComment[0]):
+			start := len(l.BlockComment[0])
+			end := strings.Index(src[i+start:], l.BlockComment[1])
+			if end < 0 {
+				end = len(src) - i
+			} else {
+				end += start + len(l.BlockComment[1])
+			}
+			res = append(res, Token{i, end, TokenComment})
+			i += end
+		case strings.ContainsRune(l.Quotes, r):
+			end := size
+			for i+end < len(src) {
+				c := src[i+end]
+				end++
+				if c == '\\' && i+end < len(src) {
+					end++
+				} else if rune(c) == r || c == '\n' {
+					break
+				}
+			}
+			res = append(res, Token{i, end, TokenString})
+			i += end
+		case unicode.IsLetter(r) || r == '_' || unicode.IsDigit(r):
+			end := strings.IndexFunc(src[i:], func(c rune) bool {
+				return !(unicode.IsLetter(c) || c == '_' || unicode.IsDigit(c) || c == '.' && unicode.IsDigit(r))
+			})
+			if end < 0 {
+				end = len(src) - i
+			}
+			if unicode.IsDigit(r) {
+				res = append(res, Token{i, end, TokenNumber})
+			} else if keywords[src[i:i+end]] {
+				res = append(res, Token{i, end, TokenKeyword})
+			}
+			i += end
+		default:
+			i += size
+		}
+	}
+	return res
+}
+
+// ======== CodeEditor =================
+
+// Draw line numbers of visible lines of text widget on canvas.
+var code_gutter = `proc tg_code_gutter {text canvas} {
+    $canvas delete all
+    set last [lindex [split [$text index end-1c] .] 0]
+    set width [font measure [$text cget -font] " $last "]
+    $canvas configure -width $width
+    set i [$text index @0,0]
+    while {[$text compare $i < end]} {
+	set d [$text dlineinfo $i]
+	if {[llength $d] == 0} break
+	$canvas create text [expr {$width - 4}] [lindex $d 1] -anchor ne \
+	    -text [lindex [split $i .] 0] -font [$text cget -font] -fill gray40
+	set i [$text index "$i +1 line"]
+    }
+}`
+
+const (
+	curLineTag = "tgcurline"
+	bracketTag = "tgbracket"
+)
+
+var brackets = map[byte]byte{'(': ')', '[': ']', '{': '}', ')': '(', ']': '[', '}': '{'}
+
+type CodeEditor struct {
+	Text
+	lexer      Lexer
+	gutter     string
+	styles     map[string]Style
+	highlight  string // command for delayed highlighting
+	pending    bool
+	onModified func()
+}
+
+// Return pointer to new CodeEditor with source "text" highlighted with "lexer" (nil for plain text).
+func NewCodeEditor(text string, lexer Lexer, flags uint) *CodeEditor {
+	t := NewText(text, flags)
+	t.initParam = " -wrap none -width 80 -height 25 -undo 1 -font TkFixedFont"
+	styles := map[string]Style{
+		TokenKeyword: {Foreground: "#00008b", Bold: true},
+		TokenBuiltin: {Foreground: "#008b8b"},
+		TokenString:  {Foreground: "#a0522d"},
+		TokenNumber:  {Foreground: "#8b008b"},
+		TokenComment: {Foreground: "#708090", Italic: true},
+	}
+	return &CodeEditor{*t, lexer, "", styles, "", false, nil}
+}
+
+func (t *CodeEditor) create(parentId string) (string, uint) {
+	id, flags := t.Text.create(parentId)
+	widgets[id] = t
+	eval(t.id + " configure -tabs [expr {4 * [font measure TkFixedFont 0]}] -tabstyle wordprocessor")
+
+	// put line numbers left of text, move text and scrollbars right
+	eval(code_gutter)
+	t.gutter = t.b.id + "." + genNextId()
+	eval("canvas " + t.gutter + " -highlightthickness 0 -background gray92")
+	eval("apply {{b} {foreach w [grid slaves $b] {grid configure $w -column [expr {[dict get [grid info $w] -column] + 1}]}}} " + t.b.id)
+	eval("grid " + t.gutter + " -row 0 -column 0 -sticky ns")
+	eval("grid columnconfigure " + t.b.id + " 0 -weight 0")
+	eval("grid columnconfigure " + t.b.id + " 1 -weight 1")
+	eval(t.id + " cget -yscrollcommand")
+	ys := ""
+	if result() != "" {
+		ys = result() + " {*}$args; "
+	}
+	proc := "tgys" + genNextId()
+	eval("proc " + proc + " {args} {" + ys + "tg_code_gutter " + t.id + " " + t.gutter + "}")
+	eval(t.id + " configure -yscrollcommand " + proc)
+	eval("bind " + t.id + " <Configure> {+tg_code_gutter " + t.id + " " + t.gutter + "}")
+
+	for kind, s := range t.styles {
+		t.Tag(codeTag(kind), s)
+	}
+	eval(t.id + " tag configure " + curLineTag + " -background #f4f4ff")
+	eval(t.id + " tag lower " + curLineTag)
+	eval(t.id + " tag configure " + bracketTag + " -background #b0e0b0")
+
+	t.highlight = addCallbackCmd(func(s string) {
+		t.pending = false
+		t.Highlight()
+	})
+	t.Text.OnModified(t.modified)
+	t.Bind("<KeyRelease>", "", t.cursorMoved)
+	t.Bind("<ButtonRelease-1>", "", t.cursorMoved)
+	eval("bind " + t.id + " <Return> {" + addCallbackCmd(t.newLine) + "; break}")
+	eval("bind " + t.id + " <braceright> {" + addCallbackCmd(t.closeBrace) + "; break}")
+	t.Highlight()
+	return id, flags
+}
+
+func codeTag(kind string) string {
+	return "tgcode" + kind
+}
+
+// Set style for tokens of "kind".
+func (t *CodeEditor) SetStyle(kind string, s Style) {
+	t.styles[kind] = s
+	if t.id != "" {
+		t.Tag(codeTag(kind), s)
+	}
+}
+
+// Set new lexer (nil for plain text) and highlight text again.
+func (t *CodeEditor) SetLexer(lexer Lexer) {
+	t.lexer = lexer
+	if t.id != "" {
+		t.Highlight()
+	}
+}
+
+// Call "f" on every change of text.
+func (t *CodeEditor) OnModified(f func()) {
+	t.onModified = f
+}
+
+func (t *CodeEditor) modified() {
+	// highlight after user stops typing, old tags stay until that
+	if !t.pending {
+		t.pending = true
+		eval("after 150 " + t.highlight)
+	}
+	eval("tg_code_gutter " + t.id + " " + t.gutter)
+	t.cursorMoved("")
+	if t.onModified != nil {
+		t.onModified()
+	}
+}
+
+// Highlight whole text with lexer.
+func (t *CodeEditor) Highlight() {
+	kinds := map[string][]int{}
+	src := t.getAll()
+	var tokens []Token
+	if t.lexer != nil {
+		tokens = t.lexer.Tokens(src)
+	}
+	offsets := make([]int, 0, len(tokens)*2)
+	for _, tk := range tokens {
+		offsets = append(offsets, tk.Offset, tk.Offset+tk.Length)
+	}
+	indexes := offsetsToIndexes(src, offsets)
+	for i, tk := range tokens {
+		kinds[tk.Kind] = append(kinds[tk.Kind], i*2)
+	}
+	// one script for all changes, so text is redrawn only once
+	var sb strings.Builder
+	for kind := range t.styles {
+		sb.WriteString(t.id + " tag remove " + codeTag(kind) + " 1.0 end\n")
+	}
+	for kind, idx := range kinds {
+		if _, ok := t.styles[kind]; !ok || len(idx) == 0 {
+			continue
+		}
+		sb.WriteString(t.id + " tag add " + codeTag(kind))
+		for _, i := range idx {
+			sb.WriteString(" " + indexes[i] + " " + indexes[i+1])
+		}
+		sb.WriteString("\n")
+	}
+	eval(sb.String())
+	eval("tg_code_gutter " + t.id + " " + t.gutter)
+}
+
+// Highlight current line and matching bracket.
+func (t *CodeEditor) cursorMoved(s string) {
+	eval(t.id + " tag remove " + curLineTag + " 1.0 end")
+	eval(t.id + " tag add " + curLineTag + " {insert linestart} {insert lineend +1c}")
+	eval(t.id + " tag remove " + bracketTag + " 1.0 end")
+
+	src := t.getAll()
+	eval(t.id + " count -chars 1.0 insert")
+	n, _ := strconv.Atoi(result())
+	pos := runeOffset(src, n)
+	// bracket after cursor or before it
+	for _, p := range []int{pos, pos - 1} {
+		if p < 0 || p >= len(src) {
+			continue
+		}
+		if _, ok := brackets[src[p]]; !ok {
+			continue
+		}
+		if m := matchBracket(src, p); m >= 0 {
+			idx := offsetsToIndexes(src, sortedPair(p, m))
+			eval(t.id + " tag add " + bracketTag + " " + idx[0] + " {" + idx[0] + " +1c} " + idx[1] + " {" + idx[1] + " +1c}")
+		}
+		break
+	}
+}
+
+func sortedPair(a, b int) []int {
+	if a > b {
+		return []int{b, a}
+	}
+	return []int{a, b}
+}
+
+// Return byte offset of rune number "n" in "s".
+func runeOffset(s string, n int) int {
+	for i := range s {
+		if n == 0 {
+			return i
+		}
+		n--
+	}
+	return len(s)
+}
+
+// Return offset of bracket matching bracket at "pos" or -1.
+func matchBracket(src string, pos int) int {
+	open := src[pos]
+	pair := brackets[open]
+	step := 1
+	if strings.IndexByte(")]}", open) >= 0 {
+		step = -1
+	}
+	depth := 0
+	for i := pos; i >= 0 && i < len(src); i += step {
+		switch src[i] {
+		case open:
+			depth++
+		case pair:
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// Insert new line with indent of current line (one more after opening bracket).
+func (t *CodeEditor) newLine(s string) {
+	if t.readOnly {
+		return
+	}
+	if r, ok := t.Selection(); ok {
+		t.DeleteRange(r.From, r.To)
+	}
+	line := t.GetRange("insert linestart", "insert")
+	indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+	if trimmed := strings.TrimSpace(line); trimmed != "" && strings.IndexByte("{([", trimmed[len(trimmed)-1]) >= 0 {
+		indent += "\t"
+	}
+	t.InsertAt("insert", "\n"+indent)
+	eval(t.id + " see insert")
+}
+
+// Insert "}" removing one indent level if it is the first char of line.
+func (t *CodeEditor) closeBrace(s string) {
+	if t.readOnly {
+		return
+	}
+	line := t.GetRange("insert linestart", "insert")
+	if line != "" && strings.TrimLeft(line, " \t") == "" && line[len(line)-1] == '\t' {
+		t.DeleteRange("insert -1c", "insert")
+	}
+	t.InsertAt("insert", "}")
+}
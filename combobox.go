@@ -0,0 +1,122 @@
+package tg
+
+import (
+	"strings"
+)
+
+// Modes of Combobox
+const (
+	ComboReadOnly = iota // value can be only selected from list
+	ComboEditable        // any value can be typed
+	ComboFilter          // typed text filters list
+)
+
+// Item of Combobox with shown text and key (f.e. id in database).
+type ComboItem struct {
+	Key  string
+	Text string
+}
+
+// Return pointer to new Combobox with texts of "items", key of selected item is returned by SelectedKey.
+func NewKeyCombobox(items []ComboItem, flags uint) *Combobox {
+	texts, keys := splitComboItems(items)
+	c := NewCombobox(texts, flags)
+	c.keys = keys
+	return c
+}
+
+func splitComboItems(items []ComboItem) ([]string, []string) {
+	texts := make([]string, len(items))
+	keys := make([]string, len(items))
+	for i, it := range items {
+		texts[i] = it.Text
+		keys[i] = it.Key
+	}
+	return texts, keys
+}
+
+// Replace items of list.
+func (c *Combobox) UpdateItems(items []ComboItem) {
+	texts, keys := splitComboItems(items)
+	c.Update(texts)
+	c.keys = keys
+}
+
+// Return key of selected item, false if no item is selected.
+func (c *Combobox) SelectedKey() (string, bool) {
+	i, _ := c.GetSelection()
+	if i < 0 || i >= len(c.keys) {
+		return "", false
+	}
+	return c.keys[i], true
+}
+
+// Select item with "key", return false if there is no such item.
+func (c *Combobox) SetKey(key string) bool {
+	i := IndexOfValueInSlice(c.keys, key)
+	if i < 0 {
+		return false
+	}
+	c.SetSelection(i)
+	return true
+}
+
+// Set ComboReadOnly (default), ComboEditable or ComboFilter mode.
+func (c *Combobox) SetMode(mode int) {
+	c.mode = mode
+	if c.id == "" {
+		return
+	}
+	if c.shown != nil {
+		c.filter("")
+	}
+	if mode == ComboReadOnly {
+		eval(c.id + " configure -state readonly")
+	} else {
+		eval(c.id + " configure -state normal")
+	}
+}
+
+// Filter list by typed text in ComboFilter mode.
+func (c *Combobox) keyRelease(s string) {
+	p := splitList(s)
+	if c.mode != ComboFilter || len(p) < 2 {
+		return
+	}
+	switch p[1] {
+	case "Up", "Down", "Left", "Right", "Return", "KP_Enter", "Escape", "Tab", "Home", "End":
+		return
+	}
+	eval(c.id + " get")
+	c.filter(result())
+}
+
+// Show in list only items which contain "text" (case insensitive), all items for empty "text".
+func (c *Combobox) filter(text string) {
+	if text == "" {
+		c.shown = nil
+		eval(c.id + " configure -values " + tklist(c.list))
+		return
+	}
+	c.shown = []int{}
+	texts := []string{}
+	text = strings.ToLower(text)
+	for i, s := range c.list {
+		if strings.Contains(strings.ToLower(s), text) {
+			c.shown = append(c.shown, i)
+			texts = append(texts, s)
+		}
+	}
+	eval(c.id + " configure -values " + tklist(texts))
+}
+
+// Return index in whole list of item "n" of shown list.
+func (c *Combobox) itemIndex(n int) int {
+	if c.shown == nil || n < 0 {
+		return n
+	}
+	if n >= len(c.shown) {
+		return -1
+	}
+	return c.shown[n]
+}
@@ -0,0 +1,286 @@
+package tg
+
+import (
+	"errors"
+	"io"
+	"os/exec"
+	"strconv"
+	"unicode/utf8"
+)
+
+const (
+	stdoutTag = "tgstdout"
+	stderrTag = "tgstderr"
+	stdinTag  = "tgstdin"
+	exitTag   = "tgexit"
+)
+
+// ======== Console =================
+type Console struct {
+	Box
+	out     *Text
+	in      *Entry
+	status  *Label
+	stop    *Button
+	restart *Button
+	name    string
+	args    []string
+	dir     string
+	env     []string
+	cmd     *exec.Cmd
+	input   chan []byte // data for stdin of running command, it is written by one goroutine
+	run     int         // number of current run, output of previous runs is ignored
+	running bool
+	onExit  func(code int, err error)
+}
+
+// Return pointer to new Console which runs command "name" with "args"
+// and shows its output. Command is started by Start after Console is added to container.
+func NewConsole(name string, args []string, flags uint) *Console {
+	b := NewBox(flags)
+	c := Console{Box: *b, name: name, args: args}
+	c.out = NewText("", Expand|ScrollY)
+	c.out.initParam = " -wrap char -width 80 -height 20 -undo 0 -font TkFixedFont"
+	c.in = NewEntry("", Expand)
+	c.status = NewLabel("", 0)
+	c.stop = NewButton("Stop", 0)
+	c.restart = NewButton("Restart", 0)
+	return &c
+}
+
+func (c *Console) create(parentId string) (string, uint) {
+	id, flags := c.widget.create(parentId)
+	widgets[id] = c
+	bx := NewBox(Horizontal)
+	c.Add(c.out, bx)
+	bx.Add(c.in, c.stop, c.restart, c.status)
+	c.out.SetReadOnly(true)
+	c.out.Tag(stderrTag, Style{Foreground: "red"})
+	c.out.Tag(stdinTag, Style{Foreground: "blue"})
+	c.out.Tag(exitTag, Style{Foreground: "gray40", Italic: true})
+	c.in.IfPressEnter(c.sendLine)
+	c.stop.IfPressed(func(s string) {
+		c.Stop()
+	})
+	c.restart.IfPressed(func(s string) {
+		c.Restart()
+	})
+	c.updateState()
+	return id, flags
+}
+
+// Set working directory for command.
+func (c *Console) SetDir(dir string) {
+	c.dir = dir
+}
+
+// Set environment for command ("KEY=value"), nil for environment of this process.
+func (c *Console) SetEnv(env []string) {
+	c.env = env
+}
+
+// Call "f" in main loop when command exits, "err" is nil if command exits with code 0.
+// Command killed by Restart is reported too (after new run is started).
+func (c *Console) OnExit(f func(code int, err error)) {
+	c.onExit = f
+}
+
+func (c *Console) Running() bool {
+	return c.running
+}
+
+// Start command, output of previous run is kept.
+func (c *Console) Start() error {
+	if c.running {
+		return nil
+	}
+	cmd := exec.Command(c.name, c.args...)
+	cmd.Dir = c.dir
+	cmd.Env = c.env
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		c.print(err.Error()+"\n", exitTag)
+		return err
+	}
+	c.run++
+	run := c.run
+	c.cmd = cmd
+	c.CloseInput()
+	c.input = make(chan []byte, 1024)
+	go writeInput(stdin, c.input)
+	c.running = true
+	c.updateState()
+
+	done := make(chan bool)
+	go c.stream(run, stdout, stdoutTag, done)
+	go c.stream(run, stderr, stderrTag, done)
+	go func() {
+		<-done
+		<-done
+		err := cmd.Wait()
+		Async(func() {
+			c.exited(run, cmd, err)
+		})
+	}()
+	return nil
+}
+
+// Kill running command.
+func (c *Console) Stop() {
+	if c.running && c.cmd.Process != nil {
+		c.cmd.Process.Kill()
+	}
+}
+
+// Kill running command and start it again.
+func (c *Console) Restart() error {
+	c.Stop()
+	c.running = false
+	return c.Start()
+}
+
+// Remove all output.
+func (c *Console) Clear() {
+	c.out.Clear()
+}
+
+// Read output of command and show it in main loop.
+func (c *Console) stream(run int, r io.Reader, tag string, done chan bool) {
+	buf := make([]byte, 4096)
+	rest := []byte{}
+	for {
+		n, err := r.Read(buf)
+		if n > 0 {
+			data := append(rest, buf[:n]...)
+			// do not split multibyte chars between reads
+			valid := validUTF8Prefix(data)
+			s := string(data[:valid])
+			rest = append([]byte{}, data[valid:]...)
+			Async(func() {
+				if run == c.run {
+					c.print(s, tag)
+				}
+			})
+		}
+		if err != nil {
+			break
+		}
+	}
+	// incomplete char at the end of output is shown as is
+	if len(rest) > 0 {
+		s := string(rest)
+		Async(func() {
+			if run == c.run {
+				c.print(s, tag)
+			}
+		})
+	}
+	done <- true
+}
+
+// Return length of "b" without incomplete UTF-8 char at the end.
+func validUTF8Prefix(b []byte) int {
+	for i := len(b) - 1; i >= 0 && i >= len(b)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(b[i]) {
+			if !utf8.FullRune(b[i:]) {
+				return i
+			}
+			break
+		}
+	}
+	return len(b)
+}
+
+func (c *Console) print(s string, tag string) {
+	c.out.InsertAt("end", s, tag)
+	eval(c.out.id + " see end")
+}
+
+func (c *Console) exited(run int, cmd *exec.Cmd, err error) {
+	code := cmd.ProcessState.ExitCode()
+	msg := "Exited with code " + strconv.Itoa(code)
+	if code < 0 && err != nil {
+		msg = "Exited: " + err.Error()
+	}
+	if run != c.run {
+		// run was killed by Restart, its state is replaced by new run
+		if c.onExit != nil {
+			c.onExit(code, err)
+		}
+		return
+	}
+	c.print("\n"+msg+"\n", exitTag)
+	c.running = false
+	c.CloseInput()
+	c.status.SetText(msg)
+	c.updateState()
+	if c.onExit != nil {
+		c.onExit(code, err)
+	}
+}
+
+// Send line from Entry to stdin of command.
+func (c *Console) sendLine(s string) {
+	if !c.running || c.input == nil {
+		return
+	}
+	c.in.Clear()
+	c.print(s+"\n", stdinTag)
+	if err := c.Send(s + "\n"); err != nil {
+		c.print(err.Error()+"\n", exitTag)
+	}
+}
+
+// Send "s" to stdin of running command, "s" is written in background in order of sending.
+func (c *Console) Send(s string) error {
+	if !c.running || c.input == nil {
+		return io.ErrClosedPipe
+	}
+	select {
+	case c.input <- []byte(s):
+		return nil
+	default:
+		return errors.New("Input of command is full!")
+	}
+}
+
+// Close stdin of running command (end of input), data sent before is written first.
+func (c *Console) CloseInput() {
+	if c.input != nil {
+		close(c.input)
+		c.input = nil
+	}
+}
+
+// Write data from "input" to "w" until "input" is closed, then close "w".
+func writeInput(w io.WriteCloser, input chan []byte) {
+	var err error
+	for b := range input {
+		// after error rest of data is dropped, command doesn't read it anymore
+		if err == nil {
+			_, err = w.Write(b)
+		}
+	}
+	w.Close()
+}
+
+func (c *Console) updateState() {
+	state := "disabled"
+	if c.running {
+		state = "normal"
+		c.status.SetText("Running")
+	}
+	eval(c.stop.id + " configure -state " + state)
+	eval(c.in.id + " configure -state " + state)
+}
@@ -0,0 +1,159 @@
+package tg
+
+import (
+	"strconv"
+	"time"
+)
+
+// ======== dateLimits =================
+// Limits and marks of dates shared by Calendar and DateRangePicker.
+type dateLimits struct {
+	min      time.Time
+	max      time.Time
+	disabled func(date time.Time) bool
+	marks    map[string]string // colors of marked dates by date in "2006-01-02" layout
+}
+
+// Allow dates only from "min" to "max", zero time means no limit.
+func (l *dateLimits) SetRange(min time.Time, max time.Time) {
+	l.min = dateOnly(min)
+	l.max = dateOnly(max)
+}
+
+// Forbid dates for which "f" returns true (f.e. weekends or holidays).
+func (l *dateLimits) SetDisabled(f func(date time.Time) bool) {
+	l.disabled = f
+}
+
+// Return true if date can be selected.
+func (l *dateLimits) Allowed(date time.Time) bool {
+	date = dateOnly(date)
+	if !l.min.IsZero() && date.Before(l.min) {
+		return false
+	}
+	if !l.max.IsZero() && date.After(l.max) {
+		return false
+	}
+	return l.disabled == nil || !l.disabled(date)
+}
+
+// Show "date" with background "color" (f.e. "#ffc0c0"), empty color removes mark.
+func (l *dateLimits) Mark(date time.Time, color string) {
+	key := date.Format("2006-01-02")
+	if color == "" {
+		delete(l.marks, key)
+		return
+	}
+	if l.marks == nil {
+		l.marks = map[string]string{}
+	}
+	l.marks[key] = color
+}
+
+func (l *dateLimits) ClearMarks() {
+	l.marks = nil
+}
+
+// Return "date" moved into range from min to max.
+func (l *dateLimits) limit(date time.Time) time.Time {
+	if !l.min.IsZero() && date.Before(l.min) {
+		return l.min
+	}
+	if !l.max.IsZero() && date.After(l.max) {
+		return l.max
+	}
+	return date
+}
+
+// ======== monthGrid =================
+// Month with navigation buttons shown in dialogs of Calendar and DateRangePicker.
+type monthGrid struct {
+	Box
+	title   *Label
+	wdays   []*Label
+	cells   []*Label
+	dates   []time.Time // dates of cells, zero time for empty cells
+	onShift func(months int)
+	onClick func(date time.Time)
+}
+
+func newMonthGrid(onShift func(months int), onClick func(date time.Time)) *monthGrid {
+	b := NewBox(0)
+	return &monthGrid{Box: *b, title: NewLabel("", 0), onShift: onShift, onClick: onClick}
+}
+
+func (g *monthGrid) create(parentId string) (string, uint) {
+	id, flags := g.widget.create(parentId)
+	widgets[id] = g
+	bx := NewBox(Horizontal)
+	grid := NewGrid(7, Expand)
+	g.Add(g.title, bx, grid)
+	for _, n := range []int{-12, -1, 1, 12} {
+		n := n
+		text := map[int]string{-12: "<<", -1: "<", 1: ">", 12: ">>"}[n]
+		b := NewButton(text, 0)
+		bx.Add(b)
+		b.Width(len(text) + 1)
+		b.IfPressed(func(s string) {
+			g.onShift(n)
+		})
+	}
+	for i := 0; i < 7; i++ {
+		l := NewLabel(" ", 0)
+		grid.Add(l)
+		g.wdays = append(g.wdays, l)
+	}
+	for i := 0; i < 42; i++ {
+		i := i
+		l := NewLabel(" ", 0)
+		grid.Add(l)
+		l.Color("black", "gray")
+		eval(l.id + " configure -anchor center")
+		l.Bind("<Button-1>", "", func(s string) {
+			if i < len(g.dates) && !g.dates[i].IsZero() {
+				g.onClick(g.dates[i])
+			}
+		})
+		g.cells = append(g.cells, l)
+	}
+	return id, flags
+}
+
+// Show month of "date", marks and limits are taken from "limits",
+// "color" returns colors of selected dates (empty colors for other ones).
+func (g *monthGrid) render(date time.Time, opts CalendarOptions, limits *dateLimits,
+	color func(date time.Time) (string, string)) {
+	year, month, _ := date.Date()
+	g.title.SetText(opts.Months[month-1] + " " + strconv.Itoa(year))
+	for i, l := range g.wdays {
+		l.SetText(opts.Weekdays[opts.weekday(i)])
+	}
+	day := time.Date(year, month, 1, 0, 0, 0, 0, time.Local)
+	skip := opts.column(day.Weekday())
+	g.dates = g.dates[:0]
+	for i, l := range g.cells {
+		if i < skip || day.Month() != month {
+			l.SetText(" ")
+			l.Color("", "gray")
+			g.dates = append(g.dates, time.Time{})
+			continue
+		}
+		fg, bg := "black", "gray"
+		if c, ok := limits.marks[day.Format("2006-01-02")]; ok {
+			bg = c
+		}
+		if !limits.Allowed(day) {
+			fg = "gray60"
+		}
+		if f, b := color(day); b != "" {
+			bg = b
+			if f != "" {
+				fg = f
+			}
+		}
+		l.SetText(strconv.Itoa(day.Day()))
+		l.Color(fg, bg)
+		g.dates = append(g.dates, day)
+		day = day.AddDate(0, 0, 1)
+	}
+}
@@ -0,0 +1,223 @@
+package tg
+
+import (
+	"errors"
+	"time"
+)
+
+// Presets of DateRangePicker
+const (
+	RangeToday = iota
+	RangeThisWeek
+	RangeThisMonth
+	RangeLast30Days
+	RangeThisQuarter
+)
+
+// Return range of preset "p" for date "now", week starts from "first" day.
+func RangePreset(p int, now time.Time, first time.Weekday) (time.Time, time.Time) {
+	today := dateOnly(now)
+	year, month, _ := today.Date()
+	switch p {
+	case RangeThisWeek:
+		from := today.AddDate(0, 0, -((int(today.Weekday()) - int(first) + 7) % 7))
+		return from, from.AddDate(0, 0, 6)
+	case RangeThisMonth:
+		from := time.Date(year, month, 1, 0, 0, 0, 0, time.Local)
+		return from, from.AddDate(0, 1, -1)
+	case RangeLast30Days:
+		return today.AddDate(0, 0, -29), today
+	case RangeThisQuarter:
+		from := time.Date(year, (month-1)/3*3+1, 1, 0, 0, 0, 0, time.Local)
+		return from, from.AddDate(0, 3, -1)
+	}
+	return today, today
+}
+
+// ======== DateRangePicker =================
+type DateRangePicker struct {
+	Box
+	dateLimits
+	lb       *Label
+	bt       *Button
+	from     time.Time
+	to       time.Time
+	selFrom  time.Time // range selected in dialog
+	selTo    time.Time
+	month    time.Time // first day of shown month
+	opts     CalendarOptions
+	tl       *Dialog
+	grid     *monthGrid
+	presets  []*Button
+	bc       *Button
+	bs       *Button
+	onChange func(from, to time.Time)
+}
+
+// Return pointer to new DateRangePicker with today as range.
+func NewDateRangePicker(flags uint) *DateRangePicker {
+	b := NewBox(flags | Horizontal)
+	today := dateOnly(time.Now())
+	d := DateRangePicker{Box: *b, lb: NewLabel("", 0), bt: NewButton(">", 0),
+		from: today, to: today, opts: calendarOptions}
+	return &d
+}
+
+func (d *DateRangePicker) create(parentId string) (string, uint) {
+	id, flags := d.widget.create(parentId)
+	widgets[id] = d
+	d.Add(d.lb, d.bt)
+	d.bt.IfPressed(d.call)
+	d.show()
+	return id, flags
+}
+
+// Set names, labels and layouts.
+func (d *DateRangePicker) SetOptions(o CalendarOptions) {
+	d.opts = o
+	if d.id == "" {
+		return
+	}
+	d.show()
+	if d.tl != nil {
+		eval("wm title " + d.tl.id + " " + tkstr(o.Title))
+		for i, b := range d.presets {
+			b.SetText(o.Presets[i])
+		}
+		d.bc.SetText(o.Cancel)
+		d.bs.SetText(o.Accept)
+		d.makeMonth()
+	}
+}
+
+// Return selected range, both dates are included.
+func (d *DateRangePicker) Value() (time.Time, time.Time) {
+	return d.from, d.to
+}
+
+// Set range, error is returned if "from" is after "to" or if one of them is not allowed.
+func (d *DateRangePicker) SetValue(from time.Time, to time.Time) error {
+	from, to = dateOnly(from), dateOnly(to)
+	if from.After(to) {
+		return errors.New("Start of range is after its end!")
+	}
+	for _, date := range []time.Time{from, to} {
+		if !d.Allowed(date) {
+			return errors.New("Date " + date.Format("2006-01-02") + " is not allowed!")
+		}
+	}
+	d.from, d.to = from, to
+	if d.id != "" {
+		d.show()
+	}
+	return nil
+}
+
+// Set range of preset (RangeToday, RangeThisWeek, RangeThisMonth, RangeLast30Days or RangeThisQuarter),
+// range is cut by limits of SetRange.
+func (d *DateRangePicker) SetPreset(p int) error {
+	from, to := RangePreset(p, time.Now(), d.opts.FirstDay)
+	return d.SetValue(d.limit(from), d.limit(to))
+}
+
+// Call "f" when user accepts range in dialog.
+func (d *DateRangePicker) OnChange(f func(from, to time.Time)) {
+	d.onChange = f
+}
+
+func (d *DateRangePicker) show() {
+	d.lb.SetText(d.from.Format(d.opts.Layout) + " – " + d.to.Format(d.opts.Layout))
+}
+
+func (d *DateRangePicker) call(s string) {
+	d.selFrom, d.selTo = d.from, d.to
+	d.month = time.Date(d.from.Year(), d.from.Month(), 1, 0, 0, 0, 0, time.Local)
+	if d.tl == nil {
+		d.tl = NewDialog(d.opts.Title, NotModal)
+		body := NewBox(Horizontal)
+		d.tl.Add(body)
+		left := NewBox(0)
+		presets := NewBox(0)
+		body.Add(left, presets)
+
+		d.grid = newMonthGrid(func(n int) {
+			d.month = d.month.AddDate(0, n, 0)
+			d.makeMonth()
+		}, d.clickDate)
+		bbx := NewBox(Horizontal)
+		left.Add(d.grid, bbx)
+
+		for p, name := range d.opts.Presets {
+			p := p
+			b := NewButton(name, 0)
+			presets.Add(b)
+			eval("pack configure " + b.id + " -fill x")
+			b.IfPressed(func(s string) {
+				from, to := RangePreset(p, time.Now(), d.opts.FirstDay)
+				d.selFrom, d.selTo = d.limit(from), d.limit(to)
+				d.month = time.Date(d.selFrom.Year(), d.selFrom.Month(), 1, 0, 0, 0, 0, time.Local)
+				d.makeMonth()
+			})
+			d.presets = append(d.presets, b)
+		}
+
+		d.bc = NewButton(d.opts.Cancel, 0)
+		d.bs = NewButton(d.opts.Accept, 0)
+		bbx.Add(d.bc, d.bs)
+		d.bc.Width(-1)
+		d.bs.Width(-1)
+		d.bc.IfPressed(func(s string) {
+			d.tl.Destroy()
+		})
+		d.bs.IfPressed(func(s string) {
+			to := d.selTo
+			if to.IsZero() {
+				to = d.selFrom
+			}
+			if d.SetValue(d.selFrom, to) != nil {
+				return
+			}
+			d.tl.Destroy()
+			if d.onChange != nil {
+				d.onChange(d.from, d.to)
+			}
+		})
+	} else {
+		eval("wm deiconify " + d.tl.id)
+	}
+	d.makeMonth()
+	d.tl.Call()
+}
+
+// Show days of month, selected range is highlighted.
+func (d *DateRangePicker) makeMonth() {
+	to := d.selTo
+	if to.IsZero() {
+		to = d.selFrom
+	}
+	d.grid.render(d.month, d.opts, &d.dateLimits, func(date time.Time) (string, string) {
+		switch {
+		case date.Equal(d.selFrom) || date.Equal(to):
+			return "white", "#4a6984"
+		case date.After(d.selFrom) && date.Before(to):
+			return "", "#b8cce0"
+		}
+		return "", ""
+	})
+}
+
+// First click selects start of range, second one selects its end.
+func (d *DateRangePicker) clickDate(date time.Time) {
+	if !d.Allowed(date) {
+		return
+	}
+	switch {
+	case !d.selTo.IsZero():
+		d.selFrom, d.selTo = date, time.Time{}
+	case date.Before(d.selFrom):
+		d.selFrom, d.selTo = date, d.selFrom
+	default:
+		d.selTo = date
+	}
+	d.makeMonth()
+}
@@ -0,0 +1,361 @@
+package tg
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Kinds of diff lines
+const (
+	DiffEqual = iota
+	DiffDelete
+	DiffInsert
+)
+
+// Modes of DiffView
+const (
+	DiffSideBySide = iota
+	DiffUnified
+)
+
+// Line of difference between two texts.
+type DiffLine struct {
+	Kind    int    // DiffEqual, DiffDelete or DiffInsert
+	Text    string // text of line without "\n"
+	OldLine int    // number of line in old text (from 1), 0 for inserted line
+	NewLine int    // number of line in new text (from 1), 0 for deleted line
+}
+
+// Return line difference between "a" and "b" (Myers algorithm).
+func DiffLines(a, b string) []DiffLine {
+	al := splitLines(a)
+	bl := splitLines(b)
+
+	// common prefix and suffix are equal lines without any search
+	pre := 0
+	for pre < len(al) && pre < len(bl) && al[pre] == bl[pre] {
+		pre++
+	}
+	suf := 0
+	for suf < len(al)-pre && suf < len(bl)-pre && al[len(al)-1-suf] == bl[len(bl)-1-suf] {
+		suf++
+	}
+
+	res := []DiffLine{}
+	for i := 0; i < pre; i++ {
+		res = append(res, DiffLine{DiffEqual, al[i], i + 1, i + 1})
+	}
+	for _, d := range myers(al[pre:len(al)-suf], bl[pre:len(bl)-suf]) {
+		if d.OldLine > 0 {
+			d.OldLine += pre
+		}
+		if d.NewLine > 0 {
+			d.NewLine += pre
+		}
+		res = append(res, d)
+	}
+	for i := suf; i > 0; i-- {
+		res = append(res, DiffLine{DiffEqual, al[len(al)-i], len(al) - i + 1, len(bl) - i + 1})
+	}
+	return res
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return []string{}
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+func myers(a, b []string) []DiffLine {
+	n, m := len(a), len(b)
+	max := n + m
+	off := max + 1
+	v := make([]int, 2*max+3)
+	trace := [][]int{}
+
+search:
+	for d := 0; d <= max; d++ {
+		// only diagonals -d-1..d+1 are used in round d, so only they are saved
+		trace = append(trace, append([]int{}, v[off-d-1:off+d+2]...))
+		for k := -d; k <= d; k += 2 {
+			x := 0
+			if k == -d || (k != d && v[off+k-1] < v[off+k+1]) {
+				x = v[off+k+1]
+			} else {
+				x = v[off+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[off+k] = x
+			if x >= n && y >= m {
+				break search
+			}
+		}
+	}
+
+	// go back from the end by saved states and collect lines in reverse order
+	rev := []DiffLine{}
+	x, y := n, m
+	for d := len(trace) - 1; d >= 0; d-- {
+		v, off := trace[d], d+1
+		k := x - y
+		prevK := k - 1
+		if k == -d || (k != d && v[off+k-1] < v[off+k+1]) {
+			prevK = k + 1
+		}
+		prevX := v[off+prevK]
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			rev = append(rev, DiffLine{DiffEqual, a[x-1], x, y})
+			x--
+			y--
+		}
+		if d > 0 {
+			if x == prevX {
+				rev = append(rev, DiffLine{DiffInsert, b[y-1], 0, y})
+			} else {
+				rev = append(rev, DiffLine{DiffDelete, a[x-1], x, 0})
+			}
+		}
+		x, y = prevX, prevY
+	}
+
+	res := make([]DiffLine, len(rev))
+	for i, d := range rev {
+		res[len(rev)-1-i] = d
+	}
+	return res
+}
+
+// ======== DiffView =================
+const (
+	diffDelTag  = "tgdiffdel"
+	diffInsTag  = "tgdiffins"
+	diffFillTag = "tgdifffill"
+	diffNumTag  = "tgdiffnum"
+	diffCurTag  = "tgdiffcur"
+)
+
+type DiffView struct {
+	Box
+	sides   *Box
+	left    *Text
+	right   *Text
+	unified *Text
+	mode    int
+	old     string
+	new     string
+	changes []int // first rows of change blocks in current mode
+	current int
+}
+
+// Return pointer to new DiffView showing difference between "old" and "new" texts
+// in "mode" (DiffSideBySide or DiffUnified).
+func NewDiffView(oldText string, newText string, mode int, flags uint) *DiffView {
+	b := NewBox(flags)
+	d := DiffView{Box: *b, mode: mode, old: oldText, new: newText, current: -1}
+	d.sides = NewBox(Horizontal | Expand)
+	d.left = NewText("", Expand)
+	d.right = NewText("", Expand)
+	d.unified = NewText("", Expand|ScrollY)
+	for _, t := range []*Text{d.left, d.right, d.unified} {
+		t.initParam = " -wrap none -width 60 -height 25 -undo 0 -font TkFixedFont"
+	}
+	return &d
+}
+
+func (d *DiffView) create(parentId string) (string, uint) {
+	id, flags := d.widget.create(parentId)
+	widgets[id] = d
+	d.Add(d.sides, d.unified)
+	d.sides.Add(d.left, d.right)
+
+	// one scrollbar for both sides, scrolling one side scrolls other
+	sb := d.sides.id + "." + genNextId()
+	eval("ttk::scrollbar " + sb + " -orient vertical -command {" +
+		"apply {{l r args} {$l yview {*}$args; $r yview {*}$args}} " + d.left.id + " " + d.right.id + "}")
+	eval("pack " + sb + " -side right -fill y")
+	for _, p := range [][2]*Text{{d.left, d.right}, {d.right, d.left}} {
+		eval(p[0].id + " configure -yscrollcommand {apply {{sb other first last} {" +
+			"$sb set $first $last; $other yview moveto $first}} " + sb + " " + p[1].id + "}")
+	}
+
+	for _, t := range []*Text{d.left, d.right, d.unified} {
+		t.SetReadOnly(true)
+		t.Tag(diffDelTag, Style{Background: "#ffd8d8"})
+		t.Tag(diffInsTag, Style{Background: "#d8ffd8"})
+		t.Tag(diffFillTag, Style{Background: "gray90"})
+		t.Tag(diffNumTag, Style{Foreground: "gray50"})
+		eval(t.id + " tag configure " + diffCurTag + " -relief raised -borderwidth 2")
+	}
+	d.SetMode(d.mode)
+	return id, flags
+}
+
+// Show difference between new texts.
+func (d *DiffView) SetTexts(oldText string, newText string) {
+	d.old = oldText
+	d.new = newText
+	d.render()
+}
+
+// Set DiffSideBySide or DiffUnified mode.
+func (d *DiffView) SetMode(mode int) {
+	d.mode = mode
+	eval("pack forget " + d.sides.id + " " + d.unified.b.id)
+	if mode == DiffUnified {
+		eval("pack " + d.unified.b.id + " -fill both -expand yes")
+	} else {
+		eval("pack " + d.sides.id + " -fill both -expand yes")
+	}
+	d.render()
+}
+
+// Return count of changed blocks.
+func (d *DiffView) ChangesCount() int {
+	return len(d.changes)
+}
+
+// Show next changed block, return false if there is no next block.
+func (d *DiffView) Next() bool {
+	if d.current+1 >= len(d.changes) {
+		return false
+	}
+	d.showChange(d.current + 1)
+	return true
+}
+
+// Show previous changed block, return false if there is no previous block.
+func (d *DiffView) Prev() bool {
+	if d.current <= 0 {
+		return false
+	}
+	d.showChange(d.current - 1)
+	return true
+}
+
+func (d *DiffView) texts() []*Text {
+	if d.mode == DiffUnified {
+		return []*Text{d.unified}
+	}
+	return []*Text{d.left, d.right}
+}
+
+func (d *DiffView) showChange(n int) {
+	d.current = n
+	row := strconv.Itoa(d.changes[n])
+	for _, t := range d.texts() {
+		t.RemoveTag(diffCurTag, "1.0", "end")
+		t.ApplyTag(diffCurTag, row+".0", row+".0 +1 line")
+		// show few lines before change
+		total, _ := splitIndex(t.Index("end"))
+		first := d.changes[n] - 4
+		if first < 0 {
+			first = 0
+		}
+		eval(t.id + " yview moveto " + strconv.FormatFloat(float64(first)/float64(total), 'f', 6, 64))
+	}
+}
+
+func (d *DiffView) render() {
+	lines := DiffLines(d.old, d.new)
+	d.changes = []int{}
+	d.current = -1
+	if d.mode == DiffUnified {
+		d.renderUnified(lines)
+	} else {
+		d.renderSides(lines)
+	}
+}
+
+func (d *DiffView) renderUnified(lines []DiffLine) {
+	t := d.unified
+	t.Clear()
+	width := len(strconv.Itoa(len(lines)))
+	num := func(n int) string {
+		if n == 0 {
+			return strings.Repeat(" ", width)
+		}
+		return fmt.Sprintf("%*d", width, n)
+	}
+	var sb strings.Builder
+	prev := DiffEqual
+	for i, l := range lines {
+		if l.Kind != DiffEqual && prev == DiffEqual {
+			d.changes = append(d.changes, i+1)
+		}
+		prev = l.Kind
+		sign, tag := " ", ""
+		switch l.Kind {
+		case DiffDelete:
+			sign, tag = "-", diffDelTag
+		case DiffInsert:
+			sign, tag = "+", diffInsTag
+		}
+		sb.WriteString(" " + tkstr(num(l.OldLine)+" "+num(l.NewLine)+" ") + " " + diffNumTag)
+		sb.WriteString(" " + tkstr(sign+" "+l.Text+"\n") + " {" + tag + "}")
+	}
+	if len(lines) > 0 {
+		t.edit(func() {
+			eval(t.id + " insert end" + sb.String())
+		})
+	}
+}
+
+// Show old text left and new text right, changed blocks are aligned with empty lines.
+func (d *DiffView) renderSides(lines []DiffLine) {
+	d.left.Clear()
+	d.right.Clear()
+	var left, right strings.Builder
+	row := 1
+	add := func(sb *strings.Builder, text string, tag string) {
+		sb.WriteString(" " + tkstr(text+"\n") + " {" + tag + "}")
+	}
+	for i := 0; i < len(lines); {
+		if lines[i].Kind == DiffEqual {
+			add(&left, lines[i].Text, "")
+			add(&right, lines[i].Text, "")
+			row++
+			i++
+			continue
+		}
+		dels, ins := []string{}, []string{}
+		for ; i < len(lines) && lines[i].Kind != DiffEqual; i++ {
+			if lines[i].Kind == DiffDelete {
+				dels = append(dels, lines[i].Text)
+			} else {
+				ins = append(ins, lines[i].Text)
+			}
+		}
+		d.changes = append(d.changes, row)
+		for j := 0; j < len(dels) || j < len(ins); j++ {
+			if j < len(dels) {
+				add(&left, dels[j], diffDelTag)
+			} else {
+				add(&left, "", diffFillTag)
+			}
+			if j < len(ins) {
+				add(&right, ins[j], diffInsTag)
+			} else {
+				add(&right, "", diffFillTag)
+			}
+			row++
+		}
+	}
+	for _, p := range []struct {
+		t  *Text
+		sb *strings.Builder
+	}{{d.left, &left}, {d.right, &right}} {
+		if p.sb.Len() == 0 {
+			continue
+		}
+		p.t.edit(func() {
+			eval(p.t.id + " insert end" + p.sb.String())
+		})
+	}
+}
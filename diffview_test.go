@@ -0,0 +1,67 @@
+package tg
+
+import (
+	"math/rand"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// Return diff as "=a:1:1 -b:2:0 +x:0:2" for comparison.
+func diffString(lines []DiffLine) string {
+	res := []string{}
+	for _, l := range lines {
+		kind := map[int]string{DiffEqual: "=", DiffDelete: "-", DiffInsert: "+"}[l.Kind]
+		res = append(res, kind+l.Text+":"+strconv.Itoa(l.OldLine)+":"+strconv.Itoa(l.NewLine))
+	}
+	return strings.Join(res, " ")
+}
+
+func TestDiffLines(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want string
+	}{
+		{"", "", ""},
+		{"a\nb\n", "a\nb", "=a:1:1 =b:2:2"},
+		{"", "a\nb", "+a:0:1 +b:0:2"},
+		{"a\nb", "", "-a:1:0 -b:2:0"},
+		{"a", "b", "-a:1:0 +b:0:1"},
+		{"a\nb\nc\n", "a\nx\nc\n", "=a:1:1 -b:2:0 +x:0:2 =c:3:3"},
+		{"a\nb\nc", "a\nc", "=a:1:1 -b:2:0 =c:3:2"},
+		{"a\nc", "a\nb\nc", "=a:1:1 +b:0:2 =c:2:3"},
+		{"a\nb\nc\nd", "b\nc\ne", "-a:1:0 =b:2:1 =c:3:2 -d:4:0 +e:0:3"},
+	}
+	for _, tt := range tests {
+		if got := diffString(DiffLines(tt.a, tt.b)); got != tt.want {
+			t.Errorf("DiffLines(%q, %q) = %q, want %q", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+// Old and new texts must be restored from diff.
+func TestDiffLinesRestore(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	random := func() []string {
+		lines := make([]string, r.Intn(40))
+		for i := range lines {
+			lines[i] = strconv.Itoa(r.Intn(4))
+		}
+		return lines
+	}
+	for i := 0; i < 300; i++ {
+		a, b := random(), random()
+		oldLines, newLines := []string{}, []string{}
+		for _, l := range myers(a, b) {
+			if l.Kind != DiffInsert {
+				oldLines = append(oldLines, l.Text)
+			}
+			if l.Kind != DiffDelete {
+				newLines = append(newLines, l.Text)
+			}
+		}
+		if strings.Join(oldLines, "\n") != strings.Join(a, "\n") || strings.Join(newLines, "\n") != strings.Join(b, "\n") {
+			t.Fatalf("myers(%q, %q) doesn't restore texts", a, b)
+		}
+	}
+}
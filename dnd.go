@@ -0,0 +1,245 @@
+package tg
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ======== Drag and drop =================
+
+// Dragged items
+type DragData struct {
+	Source interface{} // widget where drag was started (*Listbox, *Tree or *Table)
+	Items  []string    // indexes of Listbox items or ids of Tree and Table items
+	Text   string      // text shown near mouse pointer while dragging
+}
+
+// Widgets which can be source or target of drag and drop (Listbox, Tree, Table)
+type DragWidget interface {
+	Id() string
+	dragItems(x, y string) ([]string, string)
+	dropPosition(x, y string) string
+}
+
+type dropTarget struct {
+	w      DragWidget
+	accept func(d *DragData, target string) bool
+	drop   func(d *DragData, target string)
+}
+
+const dndLabel = ".tgdnd"
+
+var (
+	dropTargets = map[string]*dropTarget{}
+	dragStartX  int
+	dragStartY  int
+	dragData    *DragData
+	dragging    bool
+)
+
+// Allow to drag items from created widget "w".
+func EnableDrag(w DragWidget) {
+	id := w.Id()
+	press := addCallbackCmd(func(s string) {
+		p := strings.Split(s, " ")
+		dragStartX, _ = strconv.Atoi(p[3])
+		dragStartY, _ = strconv.Atoi(p[4])
+		dragData = nil
+		dragging = false
+		if items, text := w.dragItems(p[1], p[2]); len(items) > 0 {
+			dragData = &DragData{w, items, text}
+		}
+	})
+	motion := addCallbackCmd(func(s string) {
+		p := strings.Split(s, " ")
+		x, _ := strconv.Atoi(p[1])
+		y, _ := strconv.Atoi(p[2])
+		dragMotion(id, x, y)
+	})
+	release := addCallbackCmd(func(s string) {
+		p := strings.Split(s, " ")
+		x, _ := strconv.Atoi(p[1])
+		y, _ := strconv.Atoi(p[2])
+		dragRelease(id, x, y)
+	})
+	// break default bindings (f.e. changing selection) while dragging
+	eval("bind " + id + " <ButtonPress-1> {+" + press + " %x %y %X %Y}")
+	eval("bind " + id + " <B1-Motion> {" + motion + " %X %Y; if {$tgdragging} break}")
+	eval("bind " + id + " <ButtonRelease-1> {" + release + " %X %Y; if {$tgdragging} {set tgdragging 0; break}}")
+	SetVar("tgdragging", "0")
+}
+
+// Allow to drop items to widget "w".
+// "accept" is called while dragging over "w" and may reject items (nil accepts everything),
+// "drop" is called when items are dropped. "target" is index of Listbox item or id of Tree
+// and Table item under mouse pointer ("" for empty area).
+func AddDropTarget(w DragWidget, accept func(d *DragData, target string) bool, drop func(d *DragData, target string)) {
+	dropTargets[w.Id()] = &dropTarget{w, accept, drop}
+}
+
+func RemoveDropTarget(w DragWidget) {
+	delete(dropTargets, w.Id())
+}
+
+// Allow user to reorder items of Listbox by dragging.
+func (l *Listbox) EnableReorder() {
+	EnableDrag(l)
+	AddDropTarget(l, func(d *DragData, target string) bool {
+		return d.Source == l
+	}, func(d *DragData, target string) {
+		from, _ := strconv.Atoi(d.Items[0])
+		to, err := strconv.Atoi(target)
+		if err != nil {
+			to = len(l.Items()) - 1
+		}
+		l.Move(from, to)
+		l.SetSelection(to)
+	})
+}
+
+func dragMotion(srcId string, x, y int) {
+	if dragData == nil {
+		return
+	}
+	if !dragging {
+		if abs(x-dragStartX) < 5 && abs(y-dragStartY) < 5 {
+			return
+		}
+		dragging = true
+		SetVar("tgdragging", "1")
+		showDragLabel(dragData.Text)
+	}
+	eval("wm geometry " + dndLabel + " +" + strconv.Itoa(x+12) + "+" + strconv.Itoa(y+12))
+	cursor := "X_cursor"
+	if t, target, ok := findDropTarget(x, y); ok && t.acceptable(dragData, target) {
+		cursor = "plus"
+	}
+	eval(srcId + " configure -cursor " + cursor)
+}
+
+func dragRelease(srcId string, x, y int) {
+	if !dragging {
+		dragData = nil
+		return
+	}
+	dragging = false
+	eval("wm withdraw " + dndLabel)
+	eval(srcId + " configure -cursor {}")
+	d := dragData
+	dragData = nil
+	if t, target, ok := findDropTarget(x, y); ok && t.acceptable(d, target) {
+		t.drop(d, target)
+	}
+}
+
+// Find registered drop target under root coordinates x, y.
+func findDropTarget(x, y int) (*dropTarget, string, bool) {
+	eval("winfo containing " + strconv.Itoa(x) + " " + strconv.Itoa(y))
+	t, ok := dropTargets[result()]
+	if !ok {
+		return nil, "", false
+	}
+	id := t.w.Id()
+	eval("winfo rootx " + id)
+	rx, _ := strconv.Atoi(result())
+	eval("winfo rooty " + id)
+	ry, _ := strconv.Atoi(result())
+	return t, t.w.dropPosition(strconv.Itoa(x-rx), strconv.Itoa(y-ry)), true
+}
+
+func (t *dropTarget) acceptable(d *DragData, target string) bool {
+	return t.accept == nil || t.accept(d, target)
+}
+
+func showDragLabel(text string) {
+	eval("winfo exists " + dndLabel)
+	if result() == "0" {
+		eval("toplevel " + dndLabel)
+		eval("wm overrideredirect " + dndLabel + " 1")
+		eval("label " + dndLabel + ".l -relief solid -borderwidth 1 -background lightyellow")
+		eval("pack " + dndLabel + ".l")
+	}
+	eval(dndLabel + ".l configure -text " + tkstr(text))
+	eval("wm deiconify " + dndLabel)
+	eval("raise " + dndLabel)
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+func dragText(items []string, first string) string {
+	if len(items) > 1 {
+		return strconv.Itoa(len(items)) + " items"
+	}
+	return first
+}
+
+func (l *Listbox) dragItems(x, y string) ([]string, string) {
+	if len(l.Items()) == 0 {
+		return nil, ""
+	}
+	eval(l.id + " nearest " + y)
+	index := result()
+	eval(l.id + " get " + index)
+	return []string{index}, result()
+}
+
+func (l *Listbox) dropPosition(x, y string) string {
+	if len(l.Items()) == 0 {
+		return ""
+	}
+	eval(l.id + " nearest " + y)
+	return result()
+}
+
+// Items under pointer in treeview (whole selection if pointer is over selected item).
+func treeDragItems(id string, x, y string) []string {
+	eval(id + " identify item " + x + " " + y)
+	item := result()
+	if item == "" {
+		return nil
+	}
+	eval(id + " selection")
+	sel := splitList(result())
+	for _, s := range sel {
+		if s == item {
+			return sel
+		}
+	}
+	return []string{item}
+}
+
+func (t *Tree) dragItems(x, y string) ([]string, string) {
+	items := treeDragItems(t.id, x, y)
+	if len(items) == 0 {
+		return nil, ""
+	}
+	return items, dragText(items, t.Text(items[0]))
+}
+
+func (t *Tree) dropPosition(x, y string) string {
+	eval(t.id + " identify item " + x + " " + y)
+	return result()
+}
+
+func (t *Table) dragItems(x, y string) ([]string, string) {
+	items := treeDragItems(t.id, x, y)
+	if len(items) == 0 {
+		return nil, ""
+	}
+	eval(t.id + " item " + tkstr(items[0]) + " -values")
+	first := ""
+	if v := splitList(result()); len(v) > 0 {
+		first = v[0]
+	}
+	return items, dragText(items, first)
+}
+
+func (t *Table) dropPosition(x, y string) string {
+	eval(t.id + " identify item " + x + " " + y)
+	return result()
+}
@@ -0,0 +1,133 @@
+package tg
+
+// Link Entry with variable and watch its changes for placeholder, clear button and OnChange,
+// bind handlers of focus events.
+func (e *Entry) traceText() {
+	e.textVar = "tgentry" + genNextId()
+	SetVar(e.textVar, e.GetText())
+	eval(e.id + " configure -textvariable " + e.textVar)
+	// text may be changed few times by one edit (f.e. delete and insert), so handle it once when idle
+	changed := addCallbackCmd(func(s string) {
+		e.changePending = false
+		e.updateExtras()
+		if e.onChange != nil {
+			e.onChange(e.GetText())
+		}
+	})
+	eval("trace add variable " + e.textVar + " write " + addCallbackCmd(func(s string) {
+		if !e.changePending {
+			e.changePending = true
+			eval("after idle " + changed)
+		}
+	}))
+	e.updateExtras()
+	eval("bind " + e.id + " <FocusIn> {+" + addCallbackCmd(func(s string) {
+		if e.onFocusIn != nil {
+			e.onFocusIn()
+		}
+	}) + "}")
+	eval("bind " + e.id + " <FocusOut> {+" + addCallbackCmd(func(s string) {
+		if e.onFocusOut != nil {
+			e.onFocusOut()
+		}
+	}) + "}")
+}
+
+// Show gray hint "text" while Entry is empty, "" removes hint.
+func (e *Entry) SetPlaceholder(text string) {
+	e.placeholder = text
+	if e.id != "" {
+		e.updateExtras()
+	}
+}
+
+// Show button which clears Entry at right side of Entry while it isn't empty.
+func (e *Entry) SetClearButton(show bool) {
+	e.clearButton = show
+	if e.id != "" {
+		e.updateExtras()
+	}
+}
+
+// Call "f" with new text on every change of text.
+func (e *Entry) OnChange(f func(text string)) {
+	e.onChange = f
+}
+
+// Call "f" when Entry gets keyboard focus.
+func (e *Entry) OnFocusIn(f func()) {
+	e.onFocusIn = f
+}
+
+// Call "f" when Entry loses keyboard focus.
+func (e *Entry) OnFocusOut(f func()) {
+	e.onFocusOut = f
+}
+
+// Forbid or allow editing, text still can be selected and copied.
+func (e *Entry) SetReadOnly(ro bool) {
+	if ro {
+		eval(e.id + " state readonly")
+	} else {
+		eval(e.id + " state !readonly")
+	}
+	e.updateExtras()
+}
+
+// Disable or enable Entry.
+func (e *Entry) SetState(disabled bool) {
+	if disabled {
+		eval(e.id + " state disabled")
+	} else {
+		eval(e.id + " state !disabled")
+	}
+	e.updateExtras()
+}
+
+// Show or hide placeholder and clear button according to text and state.
+func (e *Entry) updateExtras() {
+	empty := e.GetText() == ""
+	if e.mask != nil {
+		// masked Entry shows template of mask while there is no input
+		empty = len(e.mask.raw) == 0
+	}
+	ph := e.id + ".tgph"
+	eval("winfo exists " + ph)
+	exists := result() == "1"
+	if e.placeholder != "" && !exists {
+		eval("ttk::label " + ph + " -foreground gray50 -font [" + e.id + " cget -font]" +
+			" -background [ttk::style lookup TEntry -fieldbackground {} white]")
+		eval("bind " + ph + " <ButtonPress-1> {focus " + e.id + "}")
+		exists = true
+	}
+	if exists {
+		eval(ph + " configure -text " + tkstr(e.placeholder))
+		if empty && e.placeholder != "" {
+			eval("place " + ph + " -x 4 -rely 0.5 -anchor w")
+		} else {
+			eval("place forget " + ph)
+		}
+	}
+
+	cb := e.id + ".tgclear"
+	eval("winfo exists " + cb)
+	exists = result() == "1"
+	if e.clearButton && !exists {
+		eval("ttk::label " + cb + " -text × -foreground gray40 -cursor hand2" +
+			" -background [ttk::style lookup TEntry -fieldbackground {} white]")
+		eval("bind " + cb + " <ButtonPress-1> {" + addCallbackCmd(func(s string) {
+			e.Clear()
+			eval("focus " + e.id)
+		}) + "}")
+		exists = true
+	}
+	if exists {
+		eval(e.id + " instate {!readonly !disabled}")
+		editable := result() == "1"
+		if e.clearButton && !empty && editable {
+			eval("place " + cb + " -relx 1 -x -4 -rely 0.5 -anchor e")
+		} else {
+			eval("place forget " + cb)
+		}
+	}
+}
@@ -0,0 +1,380 @@
+package tg
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+type maskItem struct {
+	class   rune // '#', 'A' or '*' for input position, 0 for literal
+	literal rune
+}
+
+// Input mask of Entry, entered chars are kept in "raw" without literals.
+type inputMask struct {
+	items       []maskItem
+	slots       []int // indexes of input positions in items
+	raw         []rune
+	placeholder rune
+}
+
+func parseMask(mask string) *inputMask {
+	m := inputMask{placeholder: '_'}
+	escaped := false
+	for _, r := range mask {
+		switch {
+		case escaped:
+			m.items = append(m.items, maskItem{0, r})
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '#' || r == 'A' || r == '*':
+			m.slots = append(m.slots, len(m.items))
+			m.items = append(m.items, maskItem{r, 0})
+		default:
+			m.items = append(m.items, maskItem{0, r})
+		}
+	}
+	return &m
+}
+
+func (m *inputMask) accepts(slot int, r rune) bool {
+	switch m.items[m.slots[slot]].class {
+	case '#':
+		return unicode.IsDigit(r)
+	case 'A':
+		return unicode.IsLetter(r)
+	}
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// Return text with literals and placeholders for empty positions.
+func (m *inputMask) render() string {
+	res := make([]rune, len(m.items))
+	for i, it := range m.items {
+		res[i] = it.literal
+		if it.class != 0 {
+			res[i] = m.placeholder
+		}
+	}
+	for i, r := range m.raw {
+		res[m.slots[i]] = r
+	}
+	return string(res)
+}
+
+// Return chars which fit input positions from the first one, other chars are skipped.
+func (m *inputMask) fit(chars []rune) []rune {
+	res := []rune{}
+	for _, c := range chars {
+		if len(res) >= len(m.slots) {
+			break
+		}
+		if m.accepts(len(res), c) {
+			res = append(res, c)
+		}
+	}
+	return res
+}
+
+// Extract entered chars from pasted "text" (formatted with mask or not).
+func (m *inputMask) extract(text string, slot int) []rune {
+	r := []rune(text)
+	prefix := []rune{}
+	for _, it := range m.items {
+		if it.class != 0 {
+			break
+		}
+		prefix = append(prefix, it.literal)
+	}
+	if slot == 0 && len(r) == len(m.items) {
+		formatted := true
+		for i, it := range m.items {
+			if it.class == 0 && r[i] != it.literal {
+				formatted = false
+				break
+			}
+		}
+		if formatted {
+			res := []rune{}
+			for _, i := range m.slots {
+				if r[i] != m.placeholder {
+					res = append(res, r[i])
+				}
+			}
+			return res
+		}
+	}
+	// skip literal prefix like country code "38" in "+38 (###)"
+	if slot == 0 && len(prefix) > 0 && strings.HasPrefix(string(r), string(prefix)) {
+		r = r[len(prefix):]
+		prefix = nil
+	}
+	chars := []rune{}
+	for _, c := range r {
+		if unicode.IsLetter(c) || unicode.IsDigit(c) {
+			chars = append(chars, c)
+		}
+	}
+	if slot == 0 && len(chars) > len(m.slots) {
+		alnum := []rune{}
+		for _, c := range prefix {
+			if unicode.IsLetter(c) || unicode.IsDigit(c) {
+				alnum = append(alnum, c)
+			}
+		}
+		if len(alnum) > 0 && strings.HasPrefix(string(chars), string(alnum)) {
+			chars = chars[len(alnum):]
+		}
+	}
+	return chars
+}
+
+// Return entered chars from "text" for Entry.SetText.
+func (m *inputMask) normalize(text string, slot int) []rune {
+	return m.fit(m.extract(text, slot))
+}
+
+// Insert chars at input position "slot", return input position after inserted chars.
+func (m *inputMask) insert(slot int, chars []rune) int {
+	if slot > len(m.raw) {
+		slot = len(m.raw)
+	}
+	head := append(append([]rune{}, m.raw[:slot]...), chars...)
+	cursor := len(m.fit(head))
+	m.raw = m.fit(append(head, m.raw[slot:]...))
+	return cursor
+}
+
+// Delete chars from input positions "from" to "to" (not included).
+func (m *inputMask) delete(from, to int) {
+	if from > len(m.raw) {
+		from = len(m.raw)
+	}
+	if to > len(m.raw) {
+		to = len(m.raw)
+	}
+	m.raw = m.fit(append(append([]rune{}, m.raw[:from]...), m.raw[to:]...))
+}
+
+// Return first input position at or after position "pos" of text.
+func (m *inputMask) slotAt(pos int) int {
+	for i, p := range m.slots {
+		if p >= pos {
+			return i
+		}
+	}
+	return len(m.slots)
+}
+
+// Return position in text of input position "slot".
+func (m *inputMask) position(slot int) int {
+	if slot < len(m.slots) {
+		return m.slots[slot]
+	}
+	return len(m.items)
+}
+
+// Set input mask: "#" - digit, "A" - letter, "*" - letter or digit, "\" - next char is literal,
+// other chars are literals which are skipped by cursor (f.e. "+38 (###) ###-##-##").
+// Empty mask removes mask.
+func (e *Entry) SetMask(mask string) {
+	if mask == "" {
+		e.mask = nil
+		return
+	}
+	old := e.mask
+	e.mask = parseMask(mask)
+	if old != nil {
+		e.mask.placeholder = old.placeholder
+	}
+	if e.id == "" {
+		return
+	}
+	if old == nil {
+		// bindings stay after removing of mask and do nothing without it
+		eval("bind " + e.id + " <<Paste>>")
+		if result() == "" {
+			e.bindMask()
+		}
+		e.SetText(e.GetText())
+	} else {
+		e.SetText(string(old.raw))
+	}
+}
+
+// Set char shown for empty input positions of mask (default "_").
+func (e *Entry) SetMaskPlaceholder(r rune) {
+	if e.mask == nil {
+		return
+	}
+	e.mask.placeholder = r
+	if e.id != "" {
+		e.showMask(len(e.mask.raw))
+	}
+}
+
+// Return entered chars without literals of mask (text of Entry if there is no mask).
+func (e *Entry) GetRaw() string {
+	if e.mask == nil {
+		return e.GetText()
+	}
+	return string(e.mask.raw)
+}
+
+// Return true if all input positions of mask are filled.
+func (e *Entry) IsComplete() bool {
+	return e.mask == nil || len(e.mask.raw) == len(e.mask.slots)
+}
+
+// Show text with mask and put cursor to input position "slot".
+func (e *Entry) showMask(slot int) {
+	if slot > len(e.mask.raw) {
+		slot = len(e.mask.raw)
+	}
+	readonly := e.isReadOnly()
+	if readonly {
+		eval(e.id + " state !readonly")
+	}
+	eval(e.id + " delete 0 end")
+	eval(e.id + " insert 0 " + tkstr(e.mask.render()))
+	eval(e.id + " icursor " + strconv.Itoa(e.mask.position(slot)))
+	if readonly {
+		eval(e.id + " state readonly")
+	}
+}
+
+func (e *Entry) isReadOnly() bool {
+	eval(e.id + " instate readonly")
+	return result() == "1"
+}
+
+func (e *Entry) bindMask() {
+	eval("bind " + e.id + " <KeyPress> {" + addCallbackCmd(e.maskKey) + " %K %A; if {$tgbreak} break}")
+	eval("bind " + e.id + " <<Paste>> {" + addCallbackCmd(e.maskPaste) + "; if {$tgbreak} break}")
+	eval("bind " + e.id + " <<Cut>> {" + addCallbackCmd(e.maskCut) + "; if {$tgbreak} break}")
+	eval("bind " + e.id + " <FocusIn> {+" + addCallbackCmd(func(s string) {
+		if e.mask != nil {
+			eval("after idle {" + e.id + " icursor " + strconv.Itoa(e.mask.position(len(e.mask.raw))) + "}")
+		}
+	}) + "}")
+	SetVar("tgbreak", "0")
+}
+
+// Return input positions of selected text, ok is false if there is no selection.
+func (e *Entry) maskSelection() (int, int, bool) {
+	eval(e.id + " selection present")
+	if result() != "1" {
+		return 0, 0, false
+	}
+	eval(e.id + " index sel.first")
+	from, _ := strconv.Atoi(result())
+	eval(e.id + " index sel.last")
+	to, _ := strconv.Atoi(result())
+	return e.mask.slotAt(from), e.mask.slotAt(to), true
+}
+
+func (e *Entry) cursor() int {
+	eval(e.id + " index insert")
+	pos, _ := strconv.Atoi(result())
+	return pos
+}
+
+func (e *Entry) maskKey(s string) {
+	SetVar("tgbreak", "0")
+	p := splitList(s)
+	if e.mask == nil || len(p) < 3 || e.isReadOnly() {
+		return
+	}
+	m := e.mask
+	pos := e.cursor()
+	from, to, sel := e.maskSelection()
+	cur := 0
+	switch p[1] {
+	case "BackSpace":
+		if sel {
+			m.delete(from, to)
+			cur = from
+			break
+		}
+		// previous input position before cursor
+		slot := m.slotAt(pos) - 1
+		if slot < 0 {
+			cur = 0
+			break
+		}
+		m.delete(slot, slot+1)
+		cur = slot
+	case "Delete":
+		if sel {
+			m.delete(from, to)
+			cur = from
+			break
+		}
+		slot := m.slotAt(pos)
+		m.delete(slot, slot+1)
+		cur = slot
+	default:
+		r := []rune(p[2])
+		if len(r) != 1 || !unicode.IsPrint(r[0]) {
+			// navigation and other keys are handled by Entry
+			return
+		}
+		if sel {
+			m.delete(from, to)
+			pos = m.position(from)
+		}
+		// typed literal moves cursor over it
+		if pos < len(m.items) && m.items[pos].class == 0 && m.items[pos].literal == r[0] {
+			eval(e.id + " icursor " + strconv.Itoa(pos+1))
+			SetVar("tgbreak", "1")
+			return
+		}
+		slot := m.slotAt(pos)
+		cur = slot
+		if slot < len(m.slots) && m.accepts(slot, r[0]) {
+			cur = m.insert(slot, r)
+		}
+	}
+	e.showMask(cur)
+	SetVar("tgbreak", "1")
+}
+
+func (e *Entry) maskPaste(s string) {
+	// without mask default binding of entry is used
+	SetVar("tgbreak", "0")
+	if e.mask == nil {
+		return
+	}
+	SetVar("tgbreak", "1")
+	if e.isReadOnly() {
+		return
+	}
+	if err := eval("clipboard get"); err != nil {
+		return
+	}
+	text := result()
+	slot := e.mask.slotAt(e.cursor())
+	if from, to, sel := e.maskSelection(); sel {
+		e.mask.delete(from, to)
+		slot = from
+	}
+	e.showMask(e.mask.insert(slot, e.mask.extract(text, slot)))
+}
+
+func (e *Entry) maskCut(s string) {
+	SetVar("tgbreak", "0")
+	if e.mask == nil {
+		return
+	}
+	SetVar("tgbreak", "1")
+	from, to, sel := e.maskSelection()
+	if !sel || e.isReadOnly() {
+		return
+	}
+	eval("clipboard clear")
+	eval("clipboard append -- [string range [" + e.id + " get] [" + e.id + " index sel.first] [expr {[" + e.id + " index sel.last] - 1}]]")
+	e.mask.delete(from, to)
+	e.showMask(from)
+}
@@ -0,0 +1,54 @@
+package tg
+
+import "testing"
+
+func TestInputMaskNormalize(t *testing.T) {
+	tests := []struct {
+		mask string
+		text string
+		want string // rendered text
+	}{
+		{"+38 (###) ###-##-##", "0501234567", "+38 (050) 123-45-67"},
+		{"+38 (###) ###-##-##", "+38 (050) 123-45-67", "+38 (050) 123-45-67"},
+		{"+38 (###) ###-##-##", "380501234567", "+38 (050) 123-45-67"},
+		{"+38 (###) ###-##-##", "050 12", "+38 (050) 12_-__-__"},
+		{"+38 (###) ###-##-##", "+38 (050) 12_-__-__", "+38 (050) 12_-__-__"},
+		{"##.##.####", "31a12b2024", "31.12.2024"},
+		{"AA-####", "ab1234", "ab-1234"},
+		{"AA-####", "1234", "__-____"},
+		{"**-**", "a1-b2", "a1-b2"},
+		{`\#-#`, "5", "#-5"},
+		{"###", "", "___"},
+	}
+	for _, tt := range tests {
+		m := parseMask(tt.mask)
+		m.raw = m.normalize(tt.text, 0)
+		if got := m.render(); got != tt.want {
+			t.Errorf("mask %q, text %q: got %q, want %q", tt.mask, tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestInputMaskEdit(t *testing.T) {
+	m := parseMask("(###) ###")
+	if cursor := m.insert(0, []rune("12x3")); cursor != 3 || m.render() != "(123) ___" {
+		t.Fatalf("insert: cursor %d, text %q", cursor, m.render())
+	}
+	// chars after inserted ones are moved right
+	if cursor := m.insert(1, []rune("9")); cursor != 2 || m.render() != "(192) 3__" {
+		t.Fatalf("insert in middle: cursor %d, text %q", cursor, m.render())
+	}
+	m.delete(1, 2)
+	if m.render() != "(123) ___" {
+		t.Fatalf("delete: text %q", m.render())
+	}
+	if slot := m.slotAt(4); slot != 3 {
+		t.Errorf("slotAt(4) = %d, want 3", slot)
+	}
+	if pos := m.position(3); pos != 6 {
+		t.Errorf("position(3) = %d, want 6", pos)
+	}
+	if pos := m.position(10); pos != 9 {
+		t.Errorf("position(10) = %d, want 9", pos)
+	}
+}
@@ -0,0 +1,88 @@
+package tg
+
+import (
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io/fs"
+)
+
+// ======== Icons =================
+
+// Tk image names of registered icons by key
+var icons = map[string]string{}
+
+// Upload "img" as Tk image and return its name.
+// Image is uploaded only once for every "key", next calls return the same name.
+func Icon(key string, img image.Image) (string, error) {
+	if name, ok := icons[key]; ok {
+		return name, nil
+	}
+	name := "tgicon" + genNextId()
+	if err := Upload_image(name, img); err != nil {
+		return "", err
+	}
+	icons[key] = name
+	return name, nil
+}
+
+// Decode PNG, JPEG or GIF file "path" from "fsys" (f.e. embed.FS or os.DirFS)
+// and register it as icon with key "path".
+func IconFromFS(fsys fs.FS, path string) (string, error) {
+	if name, ok := icons[path]; ok {
+		return name, nil
+	}
+	f, err := fsys.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return "", err
+	}
+	return Icon(path, img)
+}
+
+// Return Tk image name of icon registered with "key" or "" if there is no such icon.
+func IconByKey(key string) string {
+	return icons[key]
+}
+
+// Show "icon" before text of node "id" ("" to remove icon), CheckTree doesn't allow icons.
+func (t *Tree) SetIcon(id string, icon string) {
+	eval(t.id + " item " + tkstr(id) + " -image " + tkstr(icon))
+}
+
+// Show "icon" in the first column of row "id" ("" to remove icon).
+func (t *Table) SetIcon(id string, icon string) {
+	eval(t.id + " configure -show {tree headings}")
+	eval(t.id + " column #0 -width 28 -stretch false")
+	eval(t.id + " item " + tkstr(id) + " -text {} -image " + tkstr(icon))
+}
+
+// Return pointer to new Button with text and icon,
+// "compound" is icon position relative to text ("left", "right", "top", "bottom" or "image" for icon only).
+func NewIconButton(text string, icon string, compound string, flags uint) *Button {
+	b := NewButton(text, flags)
+	b.initParam += " -image " + tkstr(icon) + " -compound " + compound
+	return b
+}
+
+// Return pointer to new flat Button with icon only for tool bar, text is shown if there is no icon.
+func NewToolButton(text string, icon string) *Button {
+	b := NewIconButton(text, icon, "image", 0)
+	b.initParam += " -style Toolbutton"
+	return b
+}
+
+// Set icon for created Button, "compound" as in NewIconButton.
+func (b *Button) SetIcon(icon string, compound string) {
+	eval(b.id + " configure -image " + tkstr(icon) + " -compound " + compound)
+}
+
+// Show "icon" left of tab title.
+func (n *Notebook) SetTabIcon(tab *Tab, icon string) {
+	eval(n.id + " tab " + tab.id + " -image " + tkstr(icon) + " -compound left")
+}
@@ -0,0 +1,75 @@
+package tg
+
+import (
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io/fs"
+	"os"
+)
+
+// Return pointer to new Image widget showing "img", Tk photo is deleted with widget.
+func NewImageFromGo(img image.Image, flags uint) *Image {
+	i := NewImage("", flags)
+	i.img = img
+	return i
+}
+
+// Return pointer to new Image widget showing PNG, JPEG or GIF file "path".
+func NewImageFromFile(path string, flags uint) (*Image, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return nil, err
+	}
+	return NewImageFromGo(img, flags), nil
+}
+
+// Return pointer to new Image widget showing PNG, JPEG or GIF file "path" from "fsys" (f.e. embed.FS).
+func NewImageFromFS(fsys fs.FS, path string, flags uint) (*Image, error) {
+	f, err := fsys.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return nil, err
+	}
+	return NewImageFromGo(img, flags), nil
+}
+
+// Show "img" instead of current image.
+func (i *Image) SetImage(img image.Image) error {
+	if i.id == "" {
+		i.img = img
+		return nil
+	}
+	// new photo, so size of photo isn't kept from previous image
+	name := "tgimage" + genNextId()
+	if err := Upload_image(name, img); err != nil {
+		return err
+	}
+	eval(i.id + " configure -image " + name)
+	i.deletePhoto()
+	i.imageFile = name
+	i.owned = true
+	return nil
+}
+
+// Return name of shown Tk image.
+func (i *Image) Name() string {
+	return i.imageFile
+}
+
+func (i *Image) deletePhoto() {
+	if i.owned {
+		eval("image delete " + i.imageFile)
+		i.owned = false
+	}
+}
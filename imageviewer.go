@@ -0,0 +1,458 @@
+package tg
+
+import (
+	"image"
+	"math"
+	"strconv"
+	"strings"
+)
+
+const thumbSize = 80
+
+// ======== ImageViewer =================
+type ImageViewer struct {
+	Box
+	canvas     string
+	strip      string // canvas with thumbnails
+	photo      string
+	thumbs     []string // photos of thumbnails
+	pages      []image.Image
+	page       int
+	src        *image.NRGBA // current page after rotation
+	rotation   int          // count of clockwise rotations by 90 degrees
+	zoom       float64
+	fit        bool
+	offX       float64 // canvas position of top left corner of scaled image
+	offY       float64
+	dragX      int
+	dragY      int
+	pending    bool
+	render     string // command which renders image when idle
+	showThumbs bool
+	onPage     func(page int)
+}
+
+// Return pointer to new ImageViewer, images are fitted to window by default.
+func NewImageViewer(flags uint) *ImageViewer {
+	b := NewBox(flags)
+	v := ImageViewer{Box: *b, zoom: 1, fit: true}
+	return &v
+}
+
+func (v *ImageViewer) create(parentId string) (string, uint) {
+	id, flags := v.widget.create(parentId)
+	widgets[id] = v
+	v.canvas = id + ".view"
+	v.strip = id + ".thumbs"
+	v.photo = "tgview" + genNextId()
+	eval("image create photo " + v.photo)
+	eval("canvas " + v.canvas + " -background gray20 -highlightthickness 0 -width 600 -height 400")
+	eval("canvas " + v.strip + " -background gray30 -highlightthickness 0 -height " +
+		strconv.Itoa(thumbSize+10) + " -xscrollincrement " + strconv.Itoa(thumbSize+10))
+	eval("pack " + v.canvas + " -fill both -expand yes")
+	eval(v.canvas + " create image 0 0 -anchor nw -image " + v.photo + " -tags img")
+
+	v.render = addCallbackCmd(func(s string) {
+		v.pending = false
+		v.draw()
+	})
+	xy := func(s string) (int, int) {
+		p := strings.Split(s, " ")
+		x, _ := strconv.Atoi(p[len(p)-2])
+		y, _ := strconv.Atoi(p[len(p)-1])
+		return x, y
+	}
+	eval("bind " + v.canvas + " <Configure> {" + addCallbackCmd(func(s string) {
+		if v.fit {
+			v.Fit()
+		} else {
+			v.update()
+		}
+	}) + "}")
+	eval("bind " + v.canvas + " <ButtonPress-1> {" + addCallbackCmd(func(s string) {
+		v.dragX, v.dragY = xy(s)
+		eval(v.canvas + " configure -cursor fleur")
+	}) + " %x %y}")
+	eval("bind " + v.canvas + " <B1-Motion> {" + addCallbackCmd(func(s string) {
+		x, y := xy(s)
+		v.offX += float64(x - v.dragX)
+		v.offY += float64(y - v.dragY)
+		v.dragX, v.dragY = x, y
+		v.fit = false
+		v.update()
+	}) + " %x %y}")
+	eval("bind " + v.canvas + " <ButtonRelease-1> {" + v.canvas + " configure -cursor {}}")
+	wheel := addCallbackCmd(func(s string) {
+		p := strings.Split(s, " ")
+		d, _ := strconv.Atoi(p[1])
+		x, y := xy(s)
+		factor := 1.25
+		if d < 0 {
+			factor = 1 / factor
+		}
+		v.zoomAt(v.zoom*factor, float64(x), float64(y))
+	})
+	eval("bind " + v.canvas + " <MouseWheel> {" + wheel + " %D %x %y}")
+	eval("bind " + v.canvas + " <Button-4> {" + wheel + " 1 %x %y}")
+	eval("bind " + v.canvas + " <Button-5> {" + wheel + " -1 %x %y}")
+	eval("bind " + v.strip + " <MouseWheel> {" + v.strip + " xview scroll [expr {%D > 0 ? -1 : 1}] units}")
+	eval("bind " + v.strip + " <Button-4> {" + v.strip + " xview scroll -1 units}")
+	eval("bind " + v.strip + " <Button-5> {" + v.strip + " xview scroll 1 units}")
+	// binding of tag stays after items are deleted, so it is shared by thumbnails of all pages
+	eval(v.strip + " bind thumb <Button-1> {" + addCallbackCmd(func(s string) {
+		for _, tag := range splitList(s) {
+			if !strings.HasPrefix(tag, "page") {
+				continue
+			}
+			if n, err := strconv.Atoi(tag[4:]); err == nil {
+				v.SetPage(n)
+				return
+			}
+		}
+	}) + " [" + v.strip + " gettags current]}")
+	eval("bind " + id + " <Destroy> {+" + addCallbackCmd(func(s string) {
+		v.deletePhotos()
+	}) + "}")
+	v.ShowThumbnails(v.showThumbs)
+	v.SetImages(v.pages)
+	return id, flags
+}
+
+// Show one image.
+func (v *ImageViewer) SetImage(img image.Image) {
+	v.SetImages([]image.Image{img})
+}
+
+// Show set of images (f.e. pages of document), first one is shown.
+func (v *ImageViewer) SetImages(pages []image.Image) {
+	v.pages = pages
+	if v.id == "" {
+		return
+	}
+	v.makeThumbs()
+	v.page = -1
+	v.SetPage(0)
+}
+
+// Show image "n" of set.
+func (v *ImageViewer) SetPage(n int) {
+	if v.id == "" {
+		return
+	}
+	if n < 0 || n >= len(v.pages) {
+		v.src = nil
+		v.update()
+		return
+	}
+	v.page = n
+	v.rotation = 0
+	v.src = toNRGBA(v.pages[n])
+	v.Fit()
+	v.markThumb()
+	if v.onPage != nil {
+		v.onPage(n)
+	}
+}
+
+func (v *ImageViewer) Page() int {
+	return v.page
+}
+
+func (v *ImageViewer) PageCount() int {
+	return len(v.pages)
+}
+
+// Call "f" when other page is shown.
+func (v *ImageViewer) OnPageChanged(f func(page int)) {
+	v.onPage = f
+}
+
+// Show or hide strip with thumbnails of pages.
+func (v *ImageViewer) ShowThumbnails(show bool) {
+	v.showThumbs = show
+	if v.id == "" {
+		return
+	}
+	if show {
+		eval("pack " + v.strip + " -fill x -side bottom -before " + v.canvas)
+	} else {
+		eval("pack forget " + v.strip)
+	}
+}
+
+// Scale image to fit window, image is fitted again when window is resized.
+func (v *ImageViewer) Fit() {
+	v.fit = true
+	// empty image and window which isn't shown yet have nothing to fit
+	cw, ch := v.size()
+	if v.src == nil || v.src.Bounds().Empty() || cw < 1 || ch < 1 {
+		v.update()
+		return
+	}
+	b := v.src.Bounds()
+	v.zoom = math.Min(cw/float64(b.Dx()), ch/float64(b.Dy()))
+	v.center()
+}
+
+// Show image in its real size.
+func (v *ImageViewer) ActualSize() {
+	v.fit = false
+	v.zoom = 1
+	v.center()
+}
+
+// Set scale of image, 1 is real size.
+func (v *ImageViewer) SetZoom(zoom float64) {
+	cw, ch := v.size()
+	v.zoomAt(zoom, cw/2, ch/2)
+}
+
+func (v *ImageViewer) Zoom() float64 {
+	return v.zoom
+}
+
+func (v *ImageViewer) ZoomIn() {
+	v.SetZoom(v.zoom * 1.25)
+}
+
+func (v *ImageViewer) ZoomOut() {
+	v.SetZoom(v.zoom / 1.25)
+}
+
+// Rotate image by 90 degrees clockwise or counterclockwise.
+func (v *ImageViewer) Rotate(clockwise bool) {
+	if v.src == nil {
+		return
+	}
+	if clockwise {
+		v.rotation = (v.rotation + 1) % 4
+	} else {
+		v.rotation = (v.rotation + 3) % 4
+	}
+	v.src = rotate90(v.src, clockwise)
+	if v.fit {
+		v.Fit()
+	} else {
+		v.center()
+	}
+}
+
+// Return rotation of shown image in degrees clockwise.
+func (v *ImageViewer) Rotation() int {
+	return v.rotation * 90
+}
+
+// Change zoom, point of image at canvas position "x", "y" stays at its place.
+func (v *ImageViewer) zoomAt(zoom float64, x, y float64) {
+	if zoom < 0.02 {
+		zoom = 0.02
+	}
+	if zoom > 32 {
+		zoom = 32
+	}
+	v.fit = false
+	v.offX = x - (x-v.offX)*zoom/v.zoom
+	v.offY = y - (y-v.offY)*zoom/v.zoom
+	v.zoom = zoom
+	v.update()
+}
+
+func (v *ImageViewer) center() {
+	if v.src != nil {
+		cw, ch := v.size()
+		b := v.src.Bounds()
+		v.offX = (cw - float64(b.Dx())*v.zoom) / 2
+		v.offY = (ch - float64(b.Dy())*v.zoom) / 2
+	}
+	v.update()
+}
+
+func (v *ImageViewer) size() (float64, float64) {
+	eval("winfo width " + v.canvas)
+	w, _ := strconv.ParseFloat(result(), 64)
+	eval("winfo height " + v.canvas)
+	h, _ := strconv.ParseFloat(result(), 64)
+	return w, h
+}
+
+// Render image when idle, so few changes are rendered once.
+func (v *ImageViewer) update() {
+	if v.id != "" && !v.pending {
+		v.pending = true
+		eval("after idle " + v.render)
+	}
+}
+
+// Scale only visible part of image in Go and show it.
+func (v *ImageViewer) draw() {
+	eval(v.photo + " blank")
+	eval(v.photo + " configure -width 1 -height 1")
+	if v.src == nil {
+		return
+	}
+	cw, ch := v.size()
+	b := v.src.Bounds()
+	scaled := image.Rect(0, 0, int(float64(b.Dx())*v.zoom+0.5), int(float64(b.Dy())*v.zoom+0.5))
+	visible := image.Rect(int(-v.offX), int(-v.offY), int(-v.offX+cw)+1, int(-v.offY+ch)+1).Intersect(scaled)
+	if visible.Empty() {
+		return
+	}
+	img := scaleRegion(v.src, v.zoom, visible)
+	eval(v.photo + " configure -width " + strconv.Itoa(visible.Dx()) + " -height " + strconv.Itoa(visible.Dy()))
+	UploadImageRegion(v.photo, img, img.Bounds(), 0, 0)
+	eval(v.canvas + " coords img " + strconv.Itoa(int(v.offX)+visible.Min.X) + " " + strconv.Itoa(int(v.offY)+visible.Min.Y))
+}
+
+func (v *ImageViewer) makeThumbs() {
+	v.deleteThumbs()
+	eval(v.strip + " delete all")
+	step := thumbSize + 10
+	for i, p := range v.pages {
+		src := toNRGBA(p)
+		b := src.Bounds()
+		if b.Empty() {
+			continue
+		}
+		zoom := math.Min(float64(thumbSize)/float64(b.Dx()), float64(thumbSize)/float64(b.Dy()))
+		// very narrow pages give thumbnails at least 1 pixel wide
+		w := int(math.Max(1, float64(b.Dx())*zoom))
+		h := int(math.Max(1, float64(b.Dy())*zoom))
+		thumb := scaleRegion(src, zoom, image.Rect(0, 0, w, h))
+		name := "tgthumb" + genNextId()
+		if err := Upload_image(name, thumb); err != nil {
+			continue
+		}
+		v.thumbs = append(v.thumbs, name)
+		x := i*step + step/2
+		tag := "page" + strconv.Itoa(i)
+		eval(v.strip + " create rectangle " + strconv.Itoa(x-step/2+2) + " 2 " + strconv.Itoa(x+step/2-2) + " " +
+			strconv.Itoa(step-2) + " -outline {} -width 3 -tags {thumb frame " + tag + "}")
+		eval(v.strip + " create image " + strconv.Itoa(x) + " " + strconv.Itoa(step/2) + " -image " + name + " -tags {thumb " + tag + "}")
+	}
+	eval(v.strip + " configure -scrollregion {0 0 " + strconv.Itoa(len(v.pages)*step) + " " + strconv.Itoa(step) + "}")
+}
+
+// Outline thumbnail of shown page and scroll strip to it.
+func (v *ImageViewer) markThumb() {
+	eval(v.strip + " itemconfigure frame -outline {}")
+	tag := "page" + strconv.Itoa(v.page)
+	eval(v.strip + " itemconfigure {frame && " + tag + "} -outline #4a90d9")
+	if len(v.pages) > 0 {
+		eval(v.strip + " xview moveto " + strconv.FormatFloat(float64(v.page)/float64(len(v.pages)), 'f', 4, 64))
+	}
+}
+
+func (v *ImageViewer) deleteThumbs() {
+	for _, t := range v.thumbs {
+		eval("image delete " + t)
+	}
+	v.thumbs = nil
+}
+
+func (v *ImageViewer) deletePhotos() {
+	v.deleteThumbs()
+	eval("image delete " + v.photo)
+}
+
+// Return "img" as NRGBA image with bounds from (0, 0).
+func toNRGBA(img image.Image) *image.NRGBA {
+	b := img.Bounds()
+	pix, stride := nrgbaPixels(img, b)
+	res := image.NewNRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	for y := 0; y < b.Dy(); y++ {
+		copy(res.Pix[y*res.Stride:(y+1)*res.Stride], pix[y*stride:])
+	}
+	return res
+}
+
+// Return image rotated by 90 degrees.
+func rotate90(src *image.NRGBA, clockwise bool) *image.NRGBA {
+	b := src.Bounds()
+	w, h := b.Dx(), b.Dy()
+	dst := image.NewNRGBA(image.Rect(0, 0, h, w))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			nx, ny := h-1-y, x
+			if !clockwise {
+				nx, ny = y, w-1-x
+			}
+			s := src.PixOffset(x, y)
+			d := dst.PixOffset(nx, ny)
+			copy(dst.Pix[d:d+4], src.Pix[s:s+4])
+		}
+	}
+	return dst
+}
+
+// Return region "r" of "src" scaled by "zoom" (r is in coordinates of scaled image).
+// Pixels are averaged when image is reduced and interpolated when it is enlarged.
+func scaleRegion(src *image.NRGBA, zoom float64, r image.Rectangle) *image.NRGBA {
+	dst := image.NewNRGBA(image.Rect(0, 0, r.Dx(), r.Dy()))
+	b := src.Bounds()
+	w, h := b.Dx(), b.Dy()
+	for dy := 0; dy < r.Dy(); dy++ {
+		row := dst.Pix[dy*dst.Stride:]
+		for dx := 0; dx < r.Dx(); dx++ {
+			var c [4]float64
+			if zoom < 1 {
+				c = areaAverage(src, float64(r.Min.X+dx)/zoom, float64(r.Min.Y+dy)/zoom,
+					float64(r.Min.X+dx+1)/zoom, float64(r.Min.Y+dy+1)/zoom)
+			} else {
+				c = bilinear(src, (float64(r.Min.X+dx)+0.5)/zoom-0.5, (float64(r.Min.Y+dy)+0.5)/zoom-0.5, w, h)
+			}
+			for i := 0; i < 4; i++ {
+				row[dx*4+i] = uint8(c[i] + 0.5)
+			}
+		}
+	}
+	return dst
+}
+
+// Return average color of pixels in box, colors are weighted by alpha.
+func areaAverage(src *image.NRGBA, x1, y1, x2, y2 float64) [4]float64 {
+	b := src.Bounds()
+	var sum [4]float64
+	total := 0.0
+	for y := int(y1); y < int(math.Ceil(y2)) && y < b.Dy(); y++ {
+		wy := math.Min(y2, float64(y+1)) - math.Max(y1, float64(y))
+		for x := int(x1); x < int(math.Ceil(x2)) && x < b.Dx(); x++ {
+			wt := wy * (math.Min(x2, float64(x+1)) - math.Max(x1, float64(x)))
+			p := src.Pix[src.PixOffset(x, y):]
+			a := float64(p[3]) * wt
+			sum[0] += float64(p[0]) * a
+			sum[1] += float64(p[1]) * a
+			sum[2] += float64(p[2]) * a
+			sum[3] += a
+			total += wt
+		}
+	}
+	if sum[3] == 0 || total == 0 {
+		return [4]float64{}
+	}
+	return [4]float64{sum[0] / sum[3], sum[1] / sum[3], sum[2] / sum[3], sum[3] / total}
+}
+
+func bilinear(src *image.NRGBA, x, y float64, w, h int) [4]float64 {
+	clamp := func(v, max int) int {
+		if v < 0 {
+			return 0
+		}
+		if v > max {
+			return max
+		}
+		return v
+	}
+	x0, y0 := int(math.Floor(x)), int(math.Floor(y))
+	fx, fy := x-float64(x0), y-float64(y0)
+	var res [4]float64
+	for _, p := range [4]struct {
+		x, y int
+		wt   float64
+	}{{x0, y0, (1 - fx) * (1 - fy)}, {x0 + 1, y0, fx * (1 - fy)}, {x0, y0 + 1, (1 - fx) * fy}, {x0 + 1, y0 + 1, fx * fy}} {
+		px := src.Pix[src.PixOffset(clamp(p.x, w-1), clamp(p.y, h-1)):]
+		for i := 0; i < 4; i++ {
+			res[i] += float64(px[i]) * p.wt
+		}
+	}
+	return res
+}
@@ -0,0 +1,248 @@
+package tg
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Log levels for LogView
+const (
+	LevelDebug = iota
+	LevelInfo
+	LevelWarning
+	LevelError
+)
+
+var levelNames = []string{"DEBUG", "INFO", "WARNING", "ERROR"}
+
+type logLine struct {
+	level int
+	text  string
+}
+
+// ======== LogView =================
+type LogView struct {
+	Text
+	maxLines   int
+	minLevel   int
+	autoscroll bool
+	styles     []Style
+
+	mu        sync.Mutex
+	pending   []logLine
+	partial   []byte
+	scheduled bool
+	created   bool
+}
+
+// Return pointer to new read only Text for log output, only last "maxLines" lines are kept (0 for all).
+// LogView is io.Writer and can be used from any goroutine,
+// level of every line is guessed from words DEBUG, INFO, WARN, ERROR in it.
+func NewLogView(maxLines int, flags uint) *LogView {
+	t := NewText("", flags)
+	t.initParam = " -wrap word -width 80 -height 15 -undo 0 -font TkFixedFont"
+	styles := []Style{
+		{Foreground: "gray50"},
+		{},
+		{Foreground: "#b06000"},
+		{Foreground: "red", Bold: true},
+	}
+	return &LogView{Text: *t, maxLines: maxLines, autoscroll: true, styles: styles}
+}
+
+func (l *LogView) create(parentId string) (string, uint) {
+	id, flags := l.Text.create(parentId)
+	widgets[id] = l
+	l.SetReadOnly(true)
+	for level := range l.styles {
+		l.updateLevelTag(level)
+	}
+	l.mu.Lock()
+	l.created = true
+	l.mu.Unlock()
+	l.flush()
+	return id, flags
+}
+
+// Write "p" to log, incomplete last line is shown after next Write.
+func (l *LogView) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	data := append(l.partial, p...)
+	lines := strings.Split(string(data), "\n")
+	last := len(lines) - 1
+	l.partial = []byte(lines[last])
+	for _, s := range lines[:last] {
+		l.pending = append(l.pending, logLine{guessLevel(s), s})
+	}
+	l.schedule()
+	return len(p), nil
+}
+
+// Add message with "level" to log.
+func (l *LogView) Log(level int, format string, args ...interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, s := range strings.Split(strings.TrimRight(fmt.Sprintf(format, args...), "\n"), "\n") {
+		l.pending = append(l.pending, logLine{level, s})
+	}
+	l.schedule()
+}
+
+func (l *LogView) Debug(format string, args ...interface{}) {
+	l.Log(LevelDebug, format, args...)
+}
+
+func (l *LogView) Info(format string, args ...interface{}) {
+	l.Log(LevelInfo, format, args...)
+}
+
+func (l *LogView) Warning(format string, args ...interface{}) {
+	l.Log(LevelWarning, format, args...)
+}
+
+func (l *LogView) Error(format string, args ...interface{}) {
+	l.Log(LevelError, format, args...)
+}
+
+type levelWriter struct {
+	l     *LogView
+	level int
+}
+
+func (w levelWriter) Write(p []byte) (int, error) {
+	w.l.Log(w.level, "%s", p)
+	return len(p), nil
+}
+
+// Return io.Writer which adds every message to log with "level".
+func (l *LogView) LevelWriter(level int) io.Writer {
+	return levelWriter{l, level}
+}
+
+// Return log.Logger writing to log with "level" (f.e. for log.SetOutput or http.Server.ErrorLog).
+func (l *LogView) Logger(level int, prefix string) *log.Logger {
+	return log.New(l.LevelWriter(level), prefix, log.LstdFlags)
+}
+
+// Guess level of line by level names in it.
+func guessLevel(s string) int {
+	up := strings.ToUpper(s)
+	switch {
+	case strings.Contains(up, "ERROR") || strings.Contains(up, "FATAL") || strings.Contains(up, "PANIC"):
+		return LevelError
+	case strings.Contains(up, "WARN"):
+		return LevelWarning
+	case strings.Contains(up, "DEBUG"):
+		return LevelDebug
+	}
+	return LevelInfo
+}
+
+// Show pending lines in main loop (mutex must be locked).
+func (l *LogView) schedule() {
+	if l.scheduled || !l.created {
+		return
+	}
+	l.scheduled = true
+	Async(l.flush)
+}
+
+func (l *LogView) flush() {
+	l.mu.Lock()
+	lines := l.pending
+	l.pending = nil
+	l.scheduled = false
+	l.mu.Unlock()
+	if len(lines) == 0 {
+		return
+	}
+
+	// scroll to new lines only if user didn't scroll up
+	eval(l.id + " yview")
+	atEnd := strings.HasSuffix(result(), " 1.0")
+
+	var sb strings.Builder
+	for _, line := range lines {
+		sb.WriteString(" " + tkstr(line.text+"\n") + " " + levelTag(line.level))
+	}
+	l.edit(func() {
+		eval(l.id + " insert end" + sb.String())
+		if l.maxLines > 0 {
+			eval(l.id + " count -lines 1.0 end")
+			// count includes empty line after last newline
+			n, _ := strconv.Atoi(result())
+			if n-1 > l.maxLines {
+				eval(l.id + " delete 1.0 " + strconv.Itoa(n-l.maxLines) + ".0")
+			}
+		}
+	})
+	if l.autoscroll && atEnd {
+		eval(l.id + " see end")
+	}
+}
+
+func levelTag(level int) string {
+	return "tglevel" + strconv.Itoa(level)
+}
+
+func (l *LogView) updateLevelTag(level int) {
+	if level < 0 || level >= len(l.styles) {
+		return
+	}
+	s := l.styles[level]
+	s.Hidden = level < l.minLevel
+	l.Tag(levelTag(level), s)
+}
+
+// Show only lines with level "level" or higher.
+func (l *LogView) SetLevel(level int) {
+	l.minLevel = level
+	if l.id == "" {
+		return
+	}
+	for i := range l.styles {
+		l.updateLevelTag(i)
+	}
+}
+
+// Set style for lines with "level", unknown levels are ignored.
+func (l *LogView) SetLevelStyle(level int, s Style) {
+	if level < 0 || level >= len(l.styles) {
+		return
+	}
+	l.styles[level] = s
+	if l.id != "" {
+		l.updateLevelTag(level)
+	}
+}
+
+// Scroll to new lines if user didn't scroll up (default true).
+func (l *LogView) SetAutoscroll(on bool) {
+	l.autoscroll = on
+}
+
+// Save whole log to file "path".
+func (l *LogView) Save(path string) error {
+	return os.WriteFile(path, []byte(l.getAll()), 0644)
+}
+
+// Ask user for file name and save log to it.
+func (l *LogView) SaveAs() error {
+	eval("tk_getSaveFile -defaultextension .log -filetypes {{{Log files} {.log .txt}} {{All files} *}}")
+	path := result()
+	if path == "" {
+		return nil
+	}
+	return l.Save(path)
+}
+
+// Return names of levels (for Combobox with level filter).
+func LevelNames() []string {
+	return append([]string{}, levelNames...)
+}
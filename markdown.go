@@ -0,0 +1,395 @@
+package tg
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	mdBold       = "tgmdb"
+	mdItalic     = "tgmdi"
+	mdBoldItalic = "tgmdbi"
+	mdCode       = "tgmdcode"
+	mdPre        = "tgmdpre"
+	mdQuote      = "tgmdquote"
+	mdTable      = "tgmdtable"
+	mdTableHead  = "tgmdth"
+	mdRule       = "tgmdhr"
+	mdLink       = "tgmdlink"
+)
+
+var (
+	mdListRe  = regexp.MustCompile(`^(\s*)([-*+]|\d+[.)])\s+(.*)$`)
+	mdRuleRe  = regexp.MustCompile(`^(-\s*){3,}$|^(\*\s*){3,}$|^(_\s*){3,}$`)
+	mdTableRe = regexp.MustCompile(`^\|?(\s*:?-+:?\s*\|)+\s*(:?-+:?\s*)?$`)
+)
+
+// Urls of links in rendered markdown by id of Text
+var mdLinks = map[string][]string{}
+
+// Piece of text with Text tags.
+type mdSpan struct {
+	text string
+	tags []string
+}
+
+// Show markdown "src" (headings, bold and italic text, code, lists, tables, quotes and links).
+func (t *Text) SetMarkdown(src string) {
+	if t.id == "" {
+		return
+	}
+	t.initMarkdownTags()
+	spans, links := parseMarkdown(src)
+	if _, ok := mdLinks[t.id]; !ok {
+		id := t.id
+		eval("bind " + id + " <Destroy> {+" + addCallbackCmd(func(s string) {
+			delete(mdLinks, id)
+		}) + "}")
+	}
+	mdLinks[t.id] = links
+	var sb strings.Builder
+	for _, s := range spans {
+		sb.WriteString(" " + tkstr(s.text) + tklist(s.tags))
+	}
+	t.edit(func() {
+		eval(t.id + " delete 1.0 end")
+		if len(spans) > 0 {
+			eval(t.id + " insert end" + sb.String())
+		}
+	})
+}
+
+func (t *Text) initMarkdownTags() {
+	eval("font actual [" + t.id + " cget -font] -size")
+	size, _ := strconv.Atoi(result())
+	if size == 0 {
+		size = 10
+	}
+	for i, k := range []float64{2, 1.6, 1.35, 1.2, 1.1, 1} {
+		t.Tag(mdHeading(i+1), Style{Bold: true, Size: int(math.Round(float64(size) * k))})
+	}
+	t.Tag(mdBold, Style{Bold: true})
+	t.Tag(mdItalic, Style{Italic: true})
+	t.Tag(mdBoldItalic, Style{Bold: true, Italic: true})
+	t.Tag(mdQuote, Style{Foreground: "gray35", Indent: 20})
+	t.Tag(mdTable, Style{Font: "TkFixedFont"})
+	t.Tag(mdTableHead, Style{Font: "TkFixedFont", Bold: true})
+	t.Tag(mdRule, Style{Foreground: "gray60"})
+	t.Tag(mdCode, Style{Font: "TkFixedFont", Background: "gray93"})
+	t.Tag(mdPre, Style{Font: "TkFixedFont", Background: "gray93", Indent: 10})
+	t.Tag(mdLink, Style{Foreground: "blue", Underline: true})
+	// font of heading wins over bold and italic inside it
+	for i := 1; i <= 6; i++ {
+		eval(t.id + " tag raise " + mdHeading(i))
+	}
+	for level := 1; level <= 6; level++ {
+		t.Tag(mdList(level), Style{Indent: 18 * level})
+	}
+}
+
+func mdHeading(level int) string {
+	return "tgmdh" + strconv.Itoa(level)
+}
+
+func mdList(level int) string {
+	return "tgmdlist" + strconv.Itoa(level)
+}
+
+func mdLinkTag(n int) string {
+	return "tgmdlink" + strconv.Itoa(n)
+}
+
+func parseMarkdown(src string) ([]mdSpan, []string) {
+	spans := []mdSpan{}
+	links := []string{}
+	lines := strings.Split(strings.ReplaceAll(src, "\r\n", "\n"), "\n")
+	para := []string{}
+	flush := func() {
+		if len(para) > 0 {
+			spans = append(spans, parseInline(strings.Join(para, " "), nil, &links)...)
+			spans = append(spans, mdSpan{"\n\n", nil})
+			para = para[:0]
+		}
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "":
+			flush()
+
+		case strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~"):
+			flush()
+			fence := trimmed[:3]
+			code := []string{}
+			for i++; i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), fence); i++ {
+				code = append(code, lines[i])
+			}
+			spans = append(spans, mdSpan{strings.Join(code, "\n") + "\n", []string{mdPre}},
+				mdSpan{"\n", nil})
+
+		case strings.HasPrefix(trimmed, "#"):
+			level := len(trimmed) - len(strings.TrimLeft(trimmed, "#"))
+			text := strings.TrimSpace(trimmed[level:])
+			// closing "#" chars are removed only if they are separated by space
+			if rest := strings.TrimRight(text, "#"); rest == "" || strings.HasSuffix(rest, " ") {
+				text = strings.TrimSpace(rest)
+			}
+			if level > 6 || !strings.HasPrefix(trimmed[level:], " ") {
+				para = append(para, trimmed)
+				continue
+			}
+			flush()
+			spans = append(spans, parseInline(text, []string{mdHeading(level)}, &links)...)
+			spans = append(spans, mdSpan{"\n", nil})
+
+		case mdRuleRe.MatchString(trimmed):
+			flush()
+			spans = append(spans, mdSpan{strings.Repeat("─", 40) + "\n", []string{mdRule}})
+
+		case strings.HasPrefix(trimmed, "|") && i+1 < len(lines) && mdTableRe.MatchString(strings.TrimSpace(lines[i+1])):
+			flush()
+			rows := [][]string{mdTableRow(trimmed)}
+			for i += 2; i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), "|"); i++ {
+				rows = append(rows, mdTableRow(strings.TrimSpace(lines[i])))
+			}
+			i--
+			spans = append(spans, mdTableSpans(rows)...)
+			spans = append(spans, mdSpan{"\n", nil})
+
+		case strings.HasPrefix(trimmed, ">"):
+			flush()
+			quote := []string{}
+			for ; i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), ">"); i++ {
+				quote = append(quote, strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(lines[i]), ">")))
+			}
+			i--
+			spans = append(spans, parseInline(strings.Join(quote, " "), []string{mdQuote}, &links)...)
+			spans = append(spans, mdSpan{"\n\n", nil})
+
+		case mdListRe.MatchString(line):
+			flush()
+			m := mdListRe.FindStringSubmatch(line)
+			level := len(strings.ReplaceAll(m[1], "\t", "  "))/2 + 1
+			if level > 6 {
+				level = 6
+			}
+			marker := m[2]
+			if strings.IndexAny(marker, "-*+") == 0 {
+				marker = "•"
+			}
+			tags := []string{mdList(level)}
+			spans = append(spans, mdSpan{marker + " ", tags})
+			spans = append(spans, parseInline(m[3], tags, &links)...)
+			spans = append(spans, mdSpan{"\n", tags})
+			if i+1 >= len(lines) || !mdListRe.MatchString(lines[i+1]) {
+				spans = append(spans, mdSpan{"\n", nil})
+			}
+
+		default:
+			para = append(para, trimmed)
+		}
+	}
+	flush()
+	return spans, links
+}
+
+// Parse inline markup: `code`, **bold**, *italic*, [link](url).
+func parseInline(s string, base []string, links *[]string) []mdSpan {
+	spans := []mdSpan{}
+	var buf strings.Builder
+	bold, italic := false, false
+	tags := func(extra ...string) []string {
+		t := append(append([]string{}, base...), extra...)
+		switch {
+		case bold && italic:
+			t = append(t, mdBoldItalic)
+		case bold:
+			t = append(t, mdBold)
+		case italic:
+			t = append(t, mdItalic)
+		}
+		return t
+	}
+	flush := func() {
+		if buf.Len() > 0 {
+			spans = append(spans, mdSpan{buf.String(), tags()})
+			buf.Reset()
+		}
+	}
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == '\\' && i+1 < len(s):
+			buf.WriteByte(s[i+1])
+			i += 2
+			continue
+		case c == '`':
+			if end := strings.IndexByte(s[i+1:], '`'); end >= 0 {
+				flush()
+				spans = append(spans, mdSpan{s[i+1 : i+1+end], append(append([]string{}, base...), mdCode)})
+				i += end + 2
+				continue
+			}
+		case strings.HasPrefix(s[i:], "**") || strings.HasPrefix(s[i:], "__"):
+			flush()
+			bold = !bold
+			i += 2
+			continue
+		case (c == '*' || c == '_') && mdEmphasis(s, i, italic):
+			flush()
+			italic = !italic
+			i++
+			continue
+		case c == '[':
+			mid := strings.Index(s[i:], "](")
+			if mid > 0 {
+				end := strings.IndexByte(s[i+mid:], ')')
+				if end > 0 {
+					flush()
+					text := s[i+1 : i+mid]
+					url := s[i+mid+2 : i+mid+end]
+					spans = append(spans, mdSpan{text, tags(mdLink, mdLinkTag(len(*links)))})
+					*links = append(*links, url)
+					i += mid + end + 1
+					continue
+				}
+			}
+		}
+		buf.WriteByte(c)
+		i++
+	}
+	flush()
+	return spans
+}
+
+// "*" and "_" open emphasis before non space char and close it after non space char (so "2 * 3" isn't italic),
+// "_" is markup only at begin or end of word (not in snake_case).
+func mdEmphasis(s string, i int, closing bool) bool {
+	before, _ := utf8.DecodeLastRuneInString(s[:i])
+	after, _ := utf8.DecodeRuneInString(s[i+1:])
+	isWord := func(r rune) bool {
+		return unicode.IsLetter(r) || unicode.IsDigit(r)
+	}
+	if closing {
+		return i > 0 && !unicode.IsSpace(before) && (s[i] == '*' || !isWord(after))
+	}
+	return i+1 < len(s) && !unicode.IsSpace(after) && (s[i] == '*' || !isWord(before))
+}
+
+func mdTableRow(line string) []string {
+	line = strings.TrimSuffix(strings.TrimPrefix(line, "|"), "|")
+	cells := strings.Split(line, "|")
+	for i, c := range cells {
+		cells[i] = strings.TrimSpace(c)
+	}
+	return cells
+}
+
+// Render table with monospace font and columns padded to the same width.
+func mdTableSpans(rows [][]string) []mdSpan {
+	widths := []int{}
+	for _, r := range rows {
+		for i, c := range r {
+			if i >= len(widths) {
+				widths = append(widths, 0)
+			}
+			if n := utf8.RuneCountInString(c); n > widths[i] {
+				widths[i] = n
+			}
+		}
+	}
+	line := func(l, m, r string) string {
+		parts := []string{}
+		for _, w := range widths {
+			parts = append(parts, strings.Repeat("─", w+2))
+		}
+		return l + strings.Join(parts, m) + r + "\n"
+	}
+	spans := []mdSpan{{line("┌", "┬", "┐"), []string{mdTable}}}
+	for n, r := range rows {
+		tag := mdTable
+		if n == 0 {
+			tag = mdTableHead
+		}
+		var sb strings.Builder
+		sb.WriteString("│")
+		for i, w := range widths {
+			c := ""
+			if i < len(r) {
+				c = r[i]
+			}
+			sb.WriteString(" " + c + strings.Repeat(" ", w-utf8.RuneCountInString(c)) + " │")
+		}
+		spans = append(spans, mdSpan{sb.String() + "\n", []string{tag}})
+		if n == 0 {
+			spans = append(spans, mdSpan{line("├", "┼", "┤"), []string{mdTable}})
+		}
+	}
+	spans = append(spans, mdSpan{line("└", "┴", "┘"), []string{mdTable}})
+	return spans
+}
+
+// ======== MarkdownView =================
+type MarkdownView struct {
+	Text
+	src    string
+	onLink func(url string)
+}
+
+// Return pointer to new read only Text showing markdown "src".
+func NewMarkdownView(src string, flags uint) *MarkdownView {
+	t := NewText("", flags)
+	t.initParam = " -wrap word -width 80 -height 25 -undo 0 -padx 6 -pady 6"
+	return &MarkdownView{*t, src, nil}
+}
+
+func (m *MarkdownView) create(parentId string) (string, uint) {
+	id, flags := m.Text.create(parentId)
+	widgets[id] = m
+	m.SetReadOnly(true)
+	m.SetMarkdown(m.src)
+	m.TagBind(mdLink, "<Enter>", "", func(s string) {
+		eval(m.id + " configure -cursor hand2")
+	})
+	m.TagBind(mdLink, "<Leave>", "", func(s string) {
+		eval(m.id + " configure -cursor {}")
+	})
+	m.TagBind(mdLink, "<Button-1>", "xy", m.click)
+	return id, flags
+}
+
+func (m *MarkdownView) SetMarkdown(src string) {
+	m.src = src
+	if m.id != "" {
+		m.Text.SetMarkdown(src)
+	}
+}
+
+// Call "f" with url of link when user clicks it.
+func (m *MarkdownView) OnLink(f func(url string)) {
+	m.onLink = f
+}
+
+func (m *MarkdownView) click(s string) {
+	p := strings.Split(s, " ")
+	if m.onLink == nil || len(p) < 3 {
+		return
+	}
+	eval(m.id + " tag names @" + p[1] + "," + p[2])
+	for _, tag := range splitList(result()) {
+		if !strings.HasPrefix(tag, mdLink) || tag == mdLink {
+			continue
+		}
+		n, err := strconv.Atoi(strings.TrimPrefix(tag, mdLink))
+		if err == nil && n < len(mdLinks[m.id]) {
+			m.onLink(mdLinks[m.id][n])
+			return
+		}
+	}
+}
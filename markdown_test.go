@@ -0,0 +1,48 @@
+package tg
+
+import (
+	"strings"
+	"testing"
+)
+
+// Return spans as "text|tag,tag" joined by "; " for comparison, new lines are shown as "\n".
+func mdString(spans []mdSpan) string {
+	res := []string{}
+	for _, s := range spans {
+		res = append(res, strings.ReplaceAll(s.text, "\n", `\n`)+"|"+strings.Join(s.tags, ","))
+	}
+	return strings.Join(res, "; ")
+}
+
+func TestParseMarkdown(t *testing.T) {
+	tests := []struct {
+		src  string
+		want string
+	}{
+		{"# Learn C#", `Learn C#|tgmdh1; \n|`},
+		{"## Title ##", `Title|tgmdh2; \n|`},
+		{"### C# ###", `C#|tgmdh3; \n|`},
+		{"#no", `#no|; \n\n|`},
+		{"2 * 3 * 4", `2 * 3 * 4|; \n\n|`},
+		{"*it* and _x_", `it|tgmdi;  and |; x|tgmdi; \n\n|`},
+		{"snake_case_name", `snake_case_name|; \n\n|`},
+		{"**b** *i*", `b|tgmdb;  |; i|tgmdi; \n\n|`},
+		{"a `x*y` b", `a |; x*y|tgmdcode;  b|; \n\n|`},
+	}
+	for _, tt := range tests {
+		spans, _ := parseMarkdown(tt.src)
+		if got := mdString(spans); got != tt.want {
+			t.Errorf("parseMarkdown(%q) = %q, want %q", tt.src, got, tt.want)
+		}
+	}
+}
+
+func TestParseMarkdownLinks(t *testing.T) {
+	spans, links := parseMarkdown("see [docs](https://example.com) and [x](y)")
+	if len(links) != 2 || links[0] != "https://example.com" || links[1] != "y" {
+		t.Fatalf("links = %q", links)
+	}
+	if got := mdString(spans[1:2]); got != "docs|"+mdLink+","+mdLinkTag(0) {
+		t.Errorf("link span = %q", got)
+	}
+}
@@ -0,0 +1,221 @@
+package tg
+
+import (
+	"strconv"
+	"time"
+)
+
+// ======== MonthView =================
+type MonthView struct {
+	widget
+	opts        CalendarOptions
+	selected    time.Time
+	weekNumbers bool
+	title       string   // id of label with month and year
+	wdays       []string // ids of weekday labels
+	weeks       []string // ids of week number labels
+	cells       []string // ids of day labels
+	dates       []time.Time
+	onSelect    func(date time.Time)
+	onAccept    func(date time.Time)
+}
+
+// Return pointer to new inline month view which can be used in any container.
+// Arrows move selection by day and week, PageUp and PageDown by month, Home selects today,
+// Enter and double click accept selected date.
+func NewMonthView(flags uint) *MonthView {
+	w := widget{"", "ttk::frame", "-takefocus 1", flags}
+	m := MonthView{widget: w, opts: calendarOptions, selected: dateOnly(time.Now())}
+	return &m
+}
+
+func (m *MonthView) create(parentId string) (string, uint) {
+	id, flags := m.widget.create(parentId)
+	widgets[id] = m
+	initMonthStyles()
+
+	head := id + ".head"
+	eval("ttk::frame " + head)
+	prev := head + ".prev"
+	next := head + ".next"
+	m.title = head + ".title"
+	eval("ttk::button " + prev + " -text < -width 2 -takefocus 0 -command {" + addCallbackCmd(func(s string) {
+		m.move(0, -1)
+	}) + "}")
+	eval("ttk::button " + next + " -text > -width 2 -takefocus 0 -command {" + addCallbackCmd(func(s string) {
+		m.move(0, 1)
+	}) + "}")
+	eval("ttk::label " + m.title + " -anchor center")
+	eval("pack " + prev + " -side left")
+	eval("pack " + next + " -side right")
+	eval("pack " + m.title + " -side left -fill x -expand yes")
+	eval("grid " + head + " -row 0 -column 0 -columnspan 8 -sticky ew")
+
+	for i := 0; i < 7; i++ {
+		l := id + ".wd" + strconv.Itoa(i)
+		eval("ttk::label " + l + " -style TgWeekday.TLabel")
+		eval("grid " + l + " -row 1 -column " + strconv.Itoa(i+1) + " -sticky nsew")
+		m.wdays = append(m.wdays, l)
+	}
+	for row := 0; row < 6; row++ {
+		w := id + ".wk" + strconv.Itoa(row)
+		eval("ttk::label " + w + " -style TgWeek.TLabel")
+		m.weeks = append(m.weeks, w)
+		for col := 0; col < 7; col++ {
+			n := len(m.cells)
+			c := id + ".d" + strconv.Itoa(n)
+			eval("ttk::label " + c + " -style TgDay.TLabel")
+			eval("grid " + c + " -row " + strconv.Itoa(row+2) + " -column " + strconv.Itoa(col+1) + " -sticky nsew")
+			eval("bind " + c + " <Button-1> {focus " + id + "; " + addCallbackCmd(func(s string) {
+				m.SetValue(m.dates[n])
+			}) + "}")
+			eval("bind " + c + " <Double-Button-1> {" + addCallbackCmd(func(s string) {
+				m.accept()
+			}) + "}")
+			m.cells = append(m.cells, c)
+		}
+	}
+	for col := 1; col < 8; col++ {
+		eval("grid columnconfigure " + id + " " + strconv.Itoa(col) + " -weight 1 -uniform tgday")
+	}
+
+	keys := map[string][2]int{"Left": {-1, 0}, "Right": {1, 0}, "Up": {-7, 0}, "Down": {7, 0},
+		"Prior": {0, -1}, "Next": {0, 1}}
+	for k, v := range keys {
+		v := v
+		eval("bind " + id + " <Key-" + k + "> {" + addCallbackCmd(func(s string) {
+			m.move(v[0], v[1])
+		}) + "; break}")
+	}
+	eval("bind " + id + " <Key-Home> {" + addCallbackCmd(func(s string) {
+		m.SetValue(time.Now())
+	}) + "}")
+	for _, k := range []string{"<Key-Return>", "<Key-KP_Enter>", "<Key-space>"} {
+		eval("bind " + id + " " + k + " {" + addCallbackCmd(func(s string) {
+			m.accept()
+		}) + "}")
+	}
+	eval("bind " + id + " <<ThemeChanged>> {+" + addCallbackCmd(func(s string) {
+		initMonthStyles()
+	}) + "}")
+	m.SetWeekNumbers(m.weekNumbers)
+	m.render()
+	return id, flags
+}
+
+// Configure styles of MonthView from colors of current theme.
+func initMonthStyles() {
+	eval("ttk::style configure TgDay.TLabel -anchor center -padding {4 2}")
+	eval("ttk::style configure TgOther.TLabel -anchor center -padding {4 2}" +
+		" -foreground [ttk::style lookup . -foreground disabled gray50]")
+	eval("ttk::style configure TgToday.TLabel -anchor center -padding {3 1} -relief solid -borderwidth 1")
+	eval("ttk::style configure TgSelected.TLabel -anchor center -padding {4 2}" +
+		" -background [ttk::style lookup . -selectbackground focus #4a6984]" +
+		" -foreground [ttk::style lookup . -selectforeground focus white]")
+	eval("ttk::style configure TgWeekday.TLabel -anchor center -font TkHeadingFont")
+	eval("ttk::style configure TgWeek.TLabel -anchor e -padding {2 2 6 2}" +
+		" -foreground [ttk::style lookup . -foreground disabled gray50]")
+}
+
+// Set names of weekdays and months and first day of week.
+func (m *MonthView) SetOptions(o CalendarOptions) {
+	m.opts = o
+	if m.id != "" {
+		m.render()
+	}
+}
+
+// Show or hide ISO week numbers.
+func (m *MonthView) SetWeekNumbers(show bool) {
+	m.weekNumbers = show
+	if m.id == "" {
+		return
+	}
+	for i, w := range m.weeks {
+		if show {
+			eval("grid " + w + " -row " + strconv.Itoa(i+2) + " -column 0 -sticky nsew")
+		} else {
+			eval("grid remove " + w)
+		}
+	}
+}
+
+func (m *MonthView) Value() time.Time {
+	return m.selected
+}
+
+// Select date and show its month.
+func (m *MonthView) SetValue(date time.Time) {
+	date = dateOnly(date)
+	if date.Equal(m.selected) {
+		return
+	}
+	m.selected = date
+	if m.id != "" {
+		m.render()
+	}
+	if m.onSelect != nil {
+		m.onSelect(date)
+	}
+}
+
+// Call "f" when selected date is changed.
+func (m *MonthView) OnSelect(f func(date time.Time)) {
+	m.onSelect = f
+}
+
+// Call "f" when selected date is accepted by Enter, Space or double click.
+func (m *MonthView) OnAccept(f func(date time.Time)) {
+	m.onAccept = f
+}
+
+func (m *MonthView) accept() {
+	if m.onAccept != nil {
+		m.onAccept(m.selected)
+	}
+}
+
+// Move selection by "days" and "months", day is limited by length of month.
+func (m *MonthView) move(days int, months int) {
+	date := m.selected.AddDate(0, 0, days)
+	if months != 0 {
+		first := time.Date(date.Year(), date.Month()+time.Month(months), 1, 0, 0, 0, 0, time.Local)
+		day := date.Day()
+		if last := first.AddDate(0, 1, -1).Day(); day > last {
+			day = last
+		}
+		date = first.AddDate(0, 0, day-1)
+	}
+	m.SetValue(date)
+}
+
+func (m *MonthView) render() {
+	year, month, _ := m.selected.Date()
+	eval(m.title + " configure -text " + tkstr(m.opts.Months[month-1]+" "+strconv.Itoa(year)))
+	for i, l := range m.wdays {
+		eval(l + " configure -text " + tkstr(m.opts.Weekdays[m.opts.weekday(i)]))
+	}
+	first := time.Date(year, month, 1, 0, 0, 0, 0, time.Local)
+	start := first.AddDate(0, 0, -m.opts.column(first.Weekday()))
+	today := dateOnly(time.Now())
+	m.dates = m.dates[:0]
+	for i, c := range m.cells {
+		date := start.AddDate(0, 0, i)
+		m.dates = append(m.dates, date)
+		style := "TgDay.TLabel"
+		switch {
+		case date.Equal(m.selected):
+			style = "TgSelected.TLabel"
+		case date.Equal(today):
+			style = "TgToday.TLabel"
+		case date.Month() != month:
+			style = "TgOther.TLabel"
+		}
+		eval(c + " configure -style " + style + " -text " + strconv.Itoa(date.Day()))
+		if i%7 == 0 {
+			// ISO week of Thursday in this row, so week doesn't depend on first day of week
+			_, week := date.AddDate(0, 0, (int(time.Thursday)-int(date.Weekday())+7)%7).ISOWeek()
+			eval(m.weeks[i/7] + " configure -text " + strconv.Itoa(week))
+		}
+	}
+}
@@ -0,0 +1,306 @@
+package tg
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ======== NumberEntry =================
+type NumberEntry struct {
+	Entry
+	decimal   string
+	thousands string
+	precision int
+	min       float64
+	max       float64
+	step      float64
+	focused   bool
+}
+
+// Return pointer to new Entry for numbers with "precision" digits after decimal separator.
+// Default decimal separator is "." and there is no thousands separator.
+func NewNumberEntry(value string, precision int, flags uint) *NumberEntry {
+	e := NewEntry(value, flags)
+	e.initParam += " -justify right -style TgNumber.TEntry"
+	step := math.Pow(10, -float64(precision))
+	return &NumberEntry{*e, ".", "", precision, math.Inf(-1), math.Inf(1), step, false}
+}
+
+func (n *NumberEntry) create(parentId string) (string, uint) {
+	eval("ttk::style map TgNumber.TEntry -foreground {invalid red}")
+	id, flags := n.Entry.create(parentId)
+	widgets[id] = n
+	eval("bind " + n.id + " <KeyPress> {" + addCallbackCmd(n.key) + " %K %A; if {$tgbreak} break}")
+	// "+" keeps focus bindings of Entry for OnFocusIn and OnFocusOut
+	eval("bind " + n.id + " <FocusIn> {+" + addCallbackCmd(func(s string) {
+		n.focused = true
+		if d, err := n.Decimal(); err == nil {
+			n.Entry.SetText(n.format(d, false))
+		}
+	}) + "}")
+	eval("bind " + n.id + " <FocusOut> {+" + addCallbackCmd(func(s string) {
+		n.focused = false
+		n.show()
+	}) + "}")
+	SetVar("tgbreak", "0")
+	n.show()
+	return id, flags
+}
+
+// Set decimal separator (f.e. ",") and thousands separator ("" for none, f.e. " " or ".").
+func (n *NumberEntry) SetSeparators(decimal string, thousands string) error {
+	if decimal == "" || decimal == thousands {
+		return errors.New("Wrong separators!")
+	}
+	d, err := n.Decimal()
+	n.decimal = decimal
+	n.thousands = thousands
+	if err == nil {
+		n.SetDecimal(d)
+	}
+	return nil
+}
+
+// Set text and show it formatted.
+func (n *NumberEntry) SetText(text string) {
+	n.Entry.SetText(text)
+	n.show()
+}
+
+// Allowed range of values.
+func (n *NumberEntry) SetRange(min float64, max float64) {
+	n.min = min
+	n.max = max
+}
+
+// Step for Up and Down keys (PageUp and PageDown change value by 10 steps).
+func (n *NumberEntry) SetStep(step float64) {
+	n.step = step
+}
+
+// Return value as decimal string with "." and exactly "precision" digits after it
+// (f.e. "-1234.50"), suitable for money. Error is returned for any wrong text.
+func (n *NumberEntry) Decimal() (string, error) {
+	if n.id == "" {
+		return n.parse(n.text)
+	}
+	return n.parse(n.GetText())
+}
+
+func (n *NumberEntry) Float64() (float64, error) {
+	d, err := n.Decimal()
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseFloat(d, 64)
+}
+
+// Return value as integer, error is returned if value has fractional part.
+func (n *NumberEntry) Int64() (int64, error) {
+	d, err := n.Decimal()
+	if err != nil {
+		return 0, err
+	}
+	parts := strings.SplitN(d, ".", 2)
+	if len(parts) == 2 && strings.Trim(parts[1], "0") != "" {
+		return 0, errors.New("Value " + d + " is not integer!")
+	}
+	return strconv.ParseInt(parts[0], 10, 64)
+}
+
+// Set value from decimal string with "." (f.e. "1234.5").
+func (n *NumberEntry) SetDecimal(d string) error {
+	canonical, err := n.canonical(d, ".", "")
+	if err != nil {
+		return err
+	}
+	n.text = n.format(canonical, false)
+	if n.id != "" {
+		n.Entry.SetText(n.text)
+		n.show()
+	}
+	return nil
+}
+
+func (n *NumberEntry) SetValue(v float64) error {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return errors.New("Wrong value!")
+	}
+	return n.SetDecimal(strconv.FormatFloat(v, 'f', n.precision, 64))
+}
+
+// Parse text with separators of this entry.
+func (n *NumberEntry) parse(text string) (string, error) {
+	return n.canonical(text, n.decimal, n.thousands)
+}
+
+// Check "text" strictly and return it as decimal string with "." and "precision" digits.
+func (n *NumberEntry) canonical(text string, decimal string, thousands string) (string, error) {
+	s := strings.TrimSpace(text)
+	wrong := errors.New("Wrong number " + strconv.Quote(text) + "!")
+	if s == "" {
+		return "", errors.New("Empty number!")
+	}
+	sign := ""
+	if s[0] == '-' || s[0] == '+' {
+		if s[0] == '-' {
+			sign = "-"
+		}
+		s = s[1:]
+	}
+	intPart, frac := s, ""
+	if i := strings.Index(s, decimal); i >= 0 {
+		intPart, frac = s[:i], s[i+len(decimal):]
+	}
+	// thousands separators are allowed only between groups of 3 digits
+	if thousands != "" && strings.Contains(intPart, thousands) {
+		groups := strings.Split(intPart, thousands)
+		for i, g := range groups {
+			if (i == 0 && (len(g) == 0 || len(g) > 3)) || (i > 0 && len(g) != 3) {
+				return "", wrong
+			}
+		}
+		intPart = strings.Join(groups, "")
+	}
+	// "-", "+", "." are not numbers
+	if intPart == "" && frac == "" {
+		return "", wrong
+	}
+	if intPart == "" {
+		intPart = "0"
+	}
+	if !allDigits(intPart) || !allDigits(frac) {
+		return "", wrong
+	}
+	if len(frac) > n.precision {
+		if strings.Trim(frac[n.precision:], "0") != "" {
+			return "", errors.New("Too many digits after decimal separator in " + strconv.Quote(text) + "!")
+		}
+		frac = frac[:n.precision]
+	}
+	frac += strings.Repeat("0", n.precision-len(frac))
+	intPart = strings.TrimLeft(intPart, "0")
+	if intPart == "" {
+		intPart = "0"
+	}
+	if strings.Trim(intPart+frac, "0") == "" {
+		sign = ""
+	}
+	res := sign + intPart
+	if n.precision > 0 {
+		res += "." + frac
+	}
+	v, err := strconv.ParseFloat(res, 64)
+	if err != nil {
+		return "", wrong
+	}
+	if v < n.min || v > n.max {
+		return "", errors.New("Value " + res + " is out of range!")
+	}
+	return res, nil
+}
+
+func allDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Format decimal string "d" with separators of entry, thousands are grouped if "group" is true.
+func (n *NumberEntry) format(d string, group bool) string {
+	sign := ""
+	if strings.HasPrefix(d, "-") {
+		sign, d = "-", d[1:]
+	}
+	parts := strings.SplitN(d, ".", 2)
+	intPart := parts[0]
+	if group && n.thousands != "" {
+		groups := []string{}
+		for len(intPart) > 3 {
+			groups = append([]string{intPart[len(intPart)-3:]}, groups...)
+			intPart = intPart[:len(intPart)-3]
+		}
+		intPart = strings.Join(append([]string{intPart}, groups...), n.thousands)
+	}
+	res := sign + intPart
+	if len(parts) == 2 {
+		res += n.decimal + parts[1]
+	}
+	return res
+}
+
+// Show formatted value if entry is not focused and mark wrong value.
+func (n *NumberEntry) show() {
+	text := n.GetText()
+	if strings.TrimSpace(text) == "" {
+		eval(n.id + " state !invalid")
+		return
+	}
+	d, err := n.parse(text)
+	if err != nil {
+		eval(n.id + " state invalid")
+		return
+	}
+	eval(n.id + " state !invalid")
+	if !n.focused {
+		n.Entry.SetText(n.format(d, true))
+	}
+}
+
+func (n *NumberEntry) key(s string) {
+	SetVar("tgbreak", "0")
+	p := splitList(s)
+	if len(p) < 3 || n.isReadOnly() {
+		return
+	}
+	switch p[1] {
+	case "Up", "Down", "Prior", "Next":
+		step := n.step
+		if p[1] == "Prior" || p[1] == "Next" {
+			step *= 10
+		}
+		if p[1] == "Down" || p[1] == "Next" {
+			step = -step
+		}
+		v, err := strconv.ParseFloat(n.mustDecimal(), 64)
+		if err != nil {
+			v = 0
+		}
+		v = math.Max(n.min, math.Min(n.max, v+step))
+		n.Entry.SetText(n.format(strconv.FormatFloat(v, 'f', n.precision, 64), false))
+		n.show()
+		SetVar("tgbreak", "1")
+		return
+	}
+	r := []rune(p[2])
+	if len(r) != 1 || !unicode.IsPrint(r[0]) {
+		return
+	}
+	c := string(r)
+	switch {
+	case c >= "0" && c <= "9" || c == "-" || c == "+" || c == n.thousands:
+		return
+	case c == n.decimal || c == "." || c == ",":
+		// any of "." and "," is decimal separator if it isn't thousands separator
+		if c != n.thousands {
+			eval("if {[" + n.id + " selection present]} {" + n.id + " delete sel.first sel.last}")
+			eval(n.id + " insert insert " + tkstr(n.decimal))
+		}
+	}
+	SetVar("tgbreak", "1")
+}
+
+// Return decimal value with "." or "0" if text is wrong.
+func (n *NumberEntry) mustDecimal() string {
+	d, err := n.Decimal()
+	if err != nil {
+		return "0"
+	}
+	return d
+}
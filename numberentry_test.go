@@ -0,0 +1,55 @@
+package tg
+
+import "testing"
+
+func TestNumberEntryCanonical(t *testing.T) {
+	tests := []struct {
+		text      string
+		decimal   string
+		thousands string
+		want      string // "" if error is expected
+	}{
+		{"1234.5", ".", "", "1234.50"},
+		{"  -1234.5 ", ".", "", "-1234.50"},
+		{"+7", ".", "", "7.00"},
+		{"1 234,56", ",", " ", "1234.56"},
+		{"1,234,567.1", ".", ",", "1234567.10"},
+		{".5", ".", "", "0.50"},
+		{"5.", ".", "", "5.00"},
+		{"007", ".", "", "7.00"},
+		{"-0", ".", "", "0.00"},
+		{"1.230", ".", "", "1.23"},
+		{"1.234", ".", "", ""},
+		{"12,34", ".", ",", ""},
+		{"1,2345", ".", ",", ""},
+		{"1a", ".", "", ""},
+		{"", ".", "", ""},
+		{"-", ".", "", ""},
+		{"+", ".", "", ""},
+		{".", ".", "", ""},
+		{"-.", ".", "", ""},
+	}
+	n := NewNumberEntry("", 2, 0)
+	for _, tt := range tests {
+		got, err := n.canonical(tt.text, tt.decimal, tt.thousands)
+		if tt.want == "" {
+			if err == nil {
+				t.Errorf("canonical(%q) = %q, want error", tt.text, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("canonical(%q) = %q, %v, want %q", tt.text, got, err, tt.want)
+		}
+	}
+}
+
+func TestNumberEntryCanonicalRange(t *testing.T) {
+	n := NewNumberEntry("", 0, 0)
+	n.SetRange(-10, 10)
+	for text, ok := range map[string]bool{"10": true, "-10": true, "11": false, "-11": false} {
+		if _, err := n.canonical(text, ".", ""); (err == nil) != ok {
+			t.Errorf("canonical(%q) error = %v, want ok %v", text, err, ok)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package tg
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+)
+
+// Return pixels of region "r" of "img" as non premultiplied RGBA rows with "stride" bytes.
+// Pixels of NRGBA image are returned without copying.
+func nrgbaPixels(img image.Image, r image.Rectangle) ([]byte, int) {
+	w, h := r.Dx(), r.Dy()
+	switch src := img.(type) {
+	case *image.NRGBA:
+		i := src.PixOffset(r.Min.X, r.Min.Y)
+		return src.Pix[i : i+(h-1)*src.Stride+w*4], src.Stride
+	case *image.RGBA:
+		pix := make([]byte, w*h*4)
+		for y := 0; y < h; y++ {
+			s := src.Pix[src.PixOffset(r.Min.X, r.Min.Y+y):]
+			d := pix[y*w*4:]
+			for x := 0; x < w*4; x += 4 {
+				a := s[x+3]
+				switch a {
+				case 0xff:
+					copy(d[x:x+4], s[x:x+4])
+				case 0:
+					d[x], d[x+1], d[x+2], d[x+3] = 0, 0, 0, 0
+				default:
+					d[x] = uint8(uint16(s[x]) * 0xff / uint16(a))
+					d[x+1] = uint8(uint16(s[x+1]) * 0xff / uint16(a))
+					d[x+2] = uint8(uint16(s[x+2]) * 0xff / uint16(a))
+					d[x+3] = a
+				}
+			}
+		}
+		return pix, w * 4
+	case *image.Gray:
+		pix := make([]byte, w*h*4)
+		for y := 0; y < h; y++ {
+			s := src.Pix[src.PixOffset(r.Min.X, r.Min.Y+y):]
+			d := pix[y*w*4:]
+			for x := 0; x < w; x++ {
+				g := s[x]
+				d[x*4], d[x*4+1], d[x*4+2], d[x*4+3] = g, g, g, 0xff
+			}
+		}
+		return pix, w * 4
+	case *image.Paletted:
+		palette := make([][4]byte, 256)
+		for i, c := range src.Palette {
+			n := color.NRGBAModel.Convert(c).(color.NRGBA)
+			palette[i] = [4]byte{n.R, n.G, n.B, n.A}
+		}
+		pix := make([]byte, w*h*4)
+		for y := 0; y < h; y++ {
+			s := src.Pix[src.PixOffset(r.Min.X, r.Min.Y+y):]
+			d := pix[y*w*4:]
+			for x := 0; x < w; x++ {
+				copy(d[x*4:x*4+4], palette[s[x]][:])
+			}
+		}
+		return pix, w * 4
+	case *image.YCbCr:
+		// YCbCr is opaque, so RGBA pixels are the same as NRGBA ones
+		dst := image.NewRGBA(image.Rect(0, 0, w, h))
+		draw.Draw(dst, dst.Bounds(), src, r.Min, draw.Src)
+		return dst.Pix, dst.Stride
+	}
+	dst := image.NewNRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(dst, dst.Bounds(), img, r.Min, draw.Src)
+	return dst.Pix, dst.Stride
+}
+
+// Return image from pixels of Tk photo block.
+func blockToImage(pix []byte, w, h, pitch, pixelSize int, offset [4]int) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	hasAlpha := offset[3] >= 0 && offset[3] < pixelSize
+	for y := 0; y < h; y++ {
+		s := pix[y*pitch:]
+		d := img.Pix[y*img.Stride:]
+		for x := 0; x < w; x++ {
+			p := s[x*pixelSize:]
+			d[x*4], d[x*4+1], d[x*4+2] = p[offset[0]], p[offset[1]], p[offset[2]]
+			d[x*4+3] = 0xff
+			if hasAlpha {
+				d[x*4+3] = p[offset[3]]
+			}
+		}
+	}
+	return img
+}
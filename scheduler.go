@@ -0,0 +1,583 @@
+package tg
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Views of Scheduler
+const (
+	SchedulerMonth = iota
+	SchedulerWeek
+)
+
+// Event shown by Scheduler, "End" is not included.
+type Event struct {
+	Id    string
+	Start time.Time
+	End   time.Time
+	Title string
+	Color string // background color, default is "#4a90d9"
+}
+
+// Rectangle of event (or of its part for one day) on canvas.
+type eventSegment struct {
+	event          int // index in events
+	x1, y1, x2, y2 float64
+	last           bool // segment with end of event
+}
+
+// ======== Scheduler =================
+type Scheduler struct {
+	widget
+	mode     int
+	date     time.Time // date in shown period
+	opts     CalendarOptions
+	fromHour int
+	toHour   int
+	source   func(from, to time.Time) []Event
+	events   []Event
+	segments []eventSegment
+	selected int
+
+	// dragging of event
+	dragEvent  int
+	dragResize bool
+	dragX      int
+	dragY      int
+	dragFrom   time.Time // time at position where dragging started
+	dragging   bool
+	preview    Event
+
+	onSelect      func(e Event)
+	onEdit        func(e Event)
+	onMove        func(e Event, start, end time.Time) bool
+	onResize      func(e Event, start, end time.Time) bool
+	onDoubleClick func(t time.Time)
+}
+
+const (
+	schedHeader = 20.0 // height of header with names of days
+	schedGutter = 44.0 // width of column with hours in week view
+	schedBar    = 15.0 // height of event in month view
+)
+
+// Return pointer to new Scheduler showing events from "source" in "mode" (SchedulerMonth or SchedulerWeek).
+// "source" is called with shown period when period is changed or Refresh is called.
+func NewScheduler(source func(from, to time.Time) []Event, mode int, flags uint) *Scheduler {
+	w := widget{"", "canvas", "-background white -highlightthickness 0 -width 700 -height 500", flags}
+	s := Scheduler{widget: w, mode: mode, date: dateOnly(time.Now()), opts: calendarOptions,
+		fromHour: 0, toHour: 24, source: source, selected: -1, dragEvent: -1}
+	return &s
+}
+
+func (s *Scheduler) create(parentId string) (string, uint) {
+	id, flags := s.widget.create(parentId)
+	widgets[id] = s
+	xy := func(str string) (int, int) {
+		p := strings.Split(str, " ")
+		x, _ := strconv.Atoi(p[len(p)-2])
+		y, _ := strconv.Atoi(p[len(p)-1])
+		return x, y
+	}
+	s.Bind("<Configure>", "", func(str string) {
+		s.draw()
+	})
+	s.Bind("<ButtonPress-1>", "xy", func(str string) {
+		s.press(xy(str))
+	})
+	s.Bind("<B1-Motion>", "xy", func(str string) {
+		s.motion(xy(str))
+	})
+	s.Bind("<ButtonRelease-1>", "xy", func(str string) {
+		s.release(xy(str))
+	})
+	s.Bind("<Double-Button-1>", "xy", func(str string) {
+		s.doubleClick(xy(str))
+	})
+	s.Bind("<Motion>", "xy", func(str string) {
+		s.hover(xy(str))
+	})
+	s.Refresh()
+	return id, flags
+}
+
+// Set SchedulerMonth or SchedulerWeek view.
+func (s *Scheduler) SetMode(mode int) {
+	s.mode = mode
+	s.Refresh()
+}
+
+// Show period with "date".
+func (s *Scheduler) SetDate(date time.Time) {
+	s.date = dateOnly(date)
+	s.Refresh()
+}
+
+// Show next month or week.
+func (s *Scheduler) Next() {
+	s.shift(1)
+}
+
+// Show previous month or week.
+func (s *Scheduler) Prev() {
+	s.shift(-1)
+}
+
+// Show period with today.
+func (s *Scheduler) Today() {
+	s.SetDate(time.Now())
+}
+
+func (s *Scheduler) shift(n int) {
+	if s.mode == SchedulerWeek {
+		s.SetDate(s.date.AddDate(0, 0, 7*n))
+		return
+	}
+	first := time.Date(s.date.Year(), s.date.Month(), 1, 0, 0, 0, 0, time.Local)
+	s.SetDate(first.AddDate(0, n, 0))
+}
+
+// Set names of weekdays and months and first day of week.
+func (s *Scheduler) SetOptions(o CalendarOptions) {
+	s.opts = o
+	s.Refresh()
+}
+
+// Show only hours from "from" to "to" (0-24) in week view.
+func (s *Scheduler) SetHours(from int, to int) {
+	if from < 0 || to > 24 || from >= to {
+		return
+	}
+	s.fromHour, s.toHour = from, to
+	s.draw()
+}
+
+// Return first shown day and day after last shown one.
+func (s *Scheduler) Period() (time.Time, time.Time) {
+	if s.mode == SchedulerWeek {
+		from := s.date.AddDate(0, 0, -s.opts.column(s.date.Weekday()))
+		return from, from.AddDate(0, 0, 7)
+	}
+	first := time.Date(s.date.Year(), s.date.Month(), 1, 0, 0, 0, 0, time.Local)
+	from := first.AddDate(0, 0, -s.opts.column(first.Weekday()))
+	return from, from.AddDate(0, 0, 42)
+}
+
+// Return text describing shown period (f.e. "October 2026").
+func (s *Scheduler) Title() string {
+	if s.mode == SchedulerWeek {
+		from, to := s.Period()
+		return from.Format(s.opts.Layout) + " – " + to.AddDate(0, 0, -1).Format(s.opts.Layout)
+	}
+	return s.opts.Months[s.date.Month()-1] + " " + strconv.Itoa(s.date.Year())
+}
+
+// Load events of shown period from source and draw them.
+func (s *Scheduler) Refresh() {
+	s.events = nil
+	if s.source != nil {
+		s.events = s.source(s.Period())
+	}
+	s.selected = -1
+	s.dragEvent = -1
+	s.dragging = false
+	s.draw()
+}
+
+// Return selected event, false if no event is selected.
+func (s *Scheduler) Selected() (Event, bool) {
+	if s.selected < 0 || s.selected >= len(s.events) {
+		return Event{}, false
+	}
+	return s.events[s.selected], true
+}
+
+// Call "f" when event is selected by click.
+func (s *Scheduler) OnSelect(f func(e Event)) {
+	s.onSelect = f
+}
+
+// Call "f" when event is double clicked.
+func (s *Scheduler) OnEdit(f func(e Event)) {
+	s.onEdit = f
+}
+
+// Call "f" when event is dragged to new time, event is moved if "f" returns true.
+func (s *Scheduler) OnMove(f func(e Event, start, end time.Time) bool) {
+	s.onMove = f
+}
+
+// Call "f" when end of event is dragged, event is changed if "f" returns true.
+func (s *Scheduler) OnResize(f func(e Event, start, end time.Time) bool) {
+	s.onResize = f
+}
+
+// Call "f" with time under pointer when empty place is double clicked (f.e. to add event).
+func (s *Scheduler) OnDoubleClick(f func(t time.Time)) {
+	s.onDoubleClick = f
+}
+
+func (s *Scheduler) size() (float64, float64) {
+	eval("winfo width " + s.id)
+	w, _ := strconv.ParseFloat(result(), 64)
+	eval("winfo height " + s.id)
+	h, _ := strconv.ParseFloat(result(), 64)
+	return w, h
+}
+
+// Return time at position on canvas, ok is false outside of days.
+func (s *Scheduler) timeAt(x, y int) (time.Time, bool) {
+	w, h := s.size()
+	from, _ := s.Period()
+	if s.mode == SchedulerWeek {
+		colW := (w - schedGutter) / 7
+		col := int((float64(x) - schedGutter) / colW)
+		if float64(x) < schedGutter || col > 6 || float64(y) < schedHeader {
+			return time.Time{}, false
+		}
+		hours := float64(s.toHour - s.fromHour)
+		minutes := (float64(y) - schedHeader) / (h - schedHeader) * hours * 60
+		// snap to 15 minutes
+		minutes = float64(int(minutes/15+0.5) * 15)
+		return from.AddDate(0, 0, col).Add(time.Duration(float64(s.fromHour*60)+minutes) * time.Minute), true
+	}
+	col := int(float64(x) / (w / 7))
+	row := int((float64(y) - schedHeader) / ((h - schedHeader) / 6))
+	if y < int(schedHeader) || col > 6 || row > 5 {
+		return time.Time{}, false
+	}
+	return from.AddDate(0, 0, row*7+col), true
+}
+
+// Return index of segment at position, -1 if there is no event.
+func (s *Scheduler) segmentAt(x, y int) int {
+	fx, fy := float64(x), float64(y)
+	for i := len(s.segments) - 1; i >= 0; i-- {
+		g := s.segments[i]
+		if fx >= g.x1 && fx <= g.x2 && fy >= g.y1 && fy <= g.y2 {
+			return i
+		}
+	}
+	return -1
+}
+
+// Return true if position is at resizing edge of segment.
+func (s *Scheduler) atEdge(g eventSegment, x, y int) bool {
+	if !g.last {
+		return false
+	}
+	if s.mode == SchedulerWeek {
+		return float64(y) >= g.y2-5
+	}
+	return float64(x) >= g.x2-5
+}
+
+func (s *Scheduler) hover(x, y int) {
+	cursor := "{}"
+	if i := s.segmentAt(x, y); i >= 0 && s.atEdge(s.segments[i], x, y) {
+		cursor = "sb_h_double_arrow"
+		if s.mode == SchedulerWeek {
+			cursor = "sb_v_double_arrow"
+		}
+	}
+	eval(s.id + " configure -cursor " + cursor)
+}
+
+func (s *Scheduler) press(x, y int) {
+	s.dragEvent = -1
+	s.dragging = false
+	i := s.segmentAt(x, y)
+	if i < 0 {
+		if s.selected >= 0 {
+			s.selected = -1
+			s.draw()
+		}
+		return
+	}
+	g := s.segments[i]
+	s.dragEvent = g.event
+	s.dragResize = s.atEdge(g, x, y)
+	s.dragX, s.dragY = x, y
+	s.dragFrom, _ = s.timeAt(x, y)
+	if s.selected != g.event {
+		s.selected = g.event
+		s.draw()
+		if s.onSelect != nil {
+			s.onSelect(s.events[g.event])
+		}
+	}
+}
+
+// Return new start and end of dragged event for pointer position.
+func (s *Scheduler) dragged(x, y int) (time.Time, time.Time, bool) {
+	e := s.events[s.dragEvent]
+	t, ok := s.timeAt(x, y)
+	if !ok {
+		return e.Start, e.End, false
+	}
+	var delta time.Duration
+	if s.mode == SchedulerWeek {
+		delta = t.Sub(s.dragFrom)
+	} else {
+		// whole days, so time of event is kept
+		days := int(math.Round(t.Sub(s.dragFrom).Hours() / 24))
+		if s.dragResize {
+			end := e.End.AddDate(0, 0, days)
+			return e.Start, end, end.After(e.Start)
+		}
+		return e.Start.AddDate(0, 0, days), e.End.AddDate(0, 0, days), true
+	}
+	if s.dragResize {
+		end := e.End.Add(delta)
+		return e.Start, end, end.After(e.Start)
+	}
+	return e.Start.Add(delta), e.End.Add(delta), true
+}
+
+func (s *Scheduler) motion(x, y int) {
+	if s.dragEvent < 0 {
+		return
+	}
+	if !s.dragging && abs(x-s.dragX) < 4 && abs(y-s.dragY) < 4 {
+		return
+	}
+	s.dragging = true
+	start, end, ok := s.dragged(x, y)
+	if !ok {
+		return
+	}
+	s.preview = s.events[s.dragEvent]
+	s.preview.Start, s.preview.End = start, end
+	s.draw()
+}
+
+func (s *Scheduler) release(x, y int) {
+	if s.dragEvent < 0 || !s.dragging {
+		s.dragEvent = -1
+		return
+	}
+	i := s.dragEvent
+	s.dragEvent = -1
+	s.dragging = false
+	start, end, ok := s.dragged(x, y)
+	e := s.events[i]
+	if ok && (!start.Equal(e.Start) || !end.Equal(e.End)) {
+		f := s.onMove
+		if s.dragResize {
+			f = s.onResize
+		}
+		if f != nil && f(e, start, end) {
+			s.events[i].Start, s.events[i].End = start, end
+		}
+	}
+	s.draw()
+}
+
+func (s *Scheduler) doubleClick(x, y int) {
+	if i := s.segmentAt(x, y); i >= 0 {
+		if s.onEdit != nil {
+			s.onEdit(s.events[s.segments[i].event])
+		}
+		return
+	}
+	if t, ok := s.timeAt(x, y); ok && s.onDoubleClick != nil {
+		s.onDoubleClick(t)
+	}
+}
+
+// Return events for drawing, dragged event is replaced by its preview.
+func (s *Scheduler) shownEvents() []Event {
+	if !s.dragging || s.dragEvent < 0 {
+		return s.events
+	}
+	events := append([]Event{}, s.events...)
+	events[s.dragEvent] = s.preview
+	return events
+}
+
+// Return indexes of events which are in period from "from" to "to" sorted by start.
+func eventsIn(events []Event, from, to time.Time) []int {
+	res := []int{}
+	for i, e := range events {
+		if e.Start.Before(to) && (e.End.After(from) || (e.End.Equal(e.Start) && !e.Start.Before(from))) {
+			res = append(res, i)
+		}
+	}
+	sort.SliceStable(res, func(a, b int) bool {
+		return events[res[a]].Start.Before(events[res[b]].Start)
+	})
+	return res
+}
+
+func (s *Scheduler) draw() {
+	if s.id == "" {
+		return
+	}
+	w, h := s.size()
+	if w < 50 || h < 50 {
+		return
+	}
+	var sb strings.Builder
+	sb.WriteString(s.id + " delete all\n")
+	s.segments = nil
+	if s.mode == SchedulerWeek {
+		s.drawWeek(&sb, w, h)
+	} else {
+		s.drawMonth(&sb, w, h)
+	}
+	eval(sb.String())
+}
+
+func (s *Scheduler) item(sb *strings.Builder, format string, args ...interface{}) {
+	sb.WriteString(s.id + " create " + fmt.Sprintf(format, args...) + "\n")
+}
+
+// Draw rectangle of event with title, selected event has thick outline.
+func (s *Scheduler) drawEvent(sb *strings.Builder, g eventSegment, e Event, title string) {
+	color := e.Color
+	if color == "" {
+		color = "#4a90d9"
+	}
+	width := 1
+	if g.event == s.selected {
+		width = 3
+	}
+	s.segments = append(s.segments, g)
+	s.item(sb, "rectangle %.1f %.1f %.1f %.1f -fill %s -outline gray30 -width %d",
+		g.x1, g.y1, g.x2, g.y2, tkstr(color), width)
+	chars := int((g.x2 - g.x1 - 6) / 7)
+	if chars <= 0 {
+		return
+	}
+	r := []rune(title)
+	if len(r) > chars {
+		r = append(r[:chars-1], '…')
+	}
+	s.item(sb, "text %.1f %.1f -anchor nw -fill white -font TkSmallCaptionFont -text %s",
+		g.x1+3, g.y1+1, tkstr(string(r)))
+}
+
+func (s *Scheduler) drawMonth(sb *strings.Builder, w, h float64) {
+	from, _ := s.Period()
+	cellW := w / 7
+	cellH := (h - schedHeader) / 6
+	today := dateOnly(time.Now())
+	events := s.shownEvents()
+	for col := 0; col < 7; col++ {
+		s.item(sb, "text %.1f %.1f -anchor n -text %s", cellW*(float64(col)+0.5), 3.0,
+			tkstr(s.opts.Weekdays[s.opts.weekday(col)]))
+	}
+	maxBars := int((cellH - 18) / (schedBar + 1))
+	for i := 0; i < 42; i++ {
+		day := from.AddDate(0, 0, i)
+		x := cellW * float64(i%7)
+		y := schedHeader + cellH*float64(i/7)
+		bg := "white"
+		if day.Month() != s.date.Month() {
+			bg = "gray95"
+		}
+		s.item(sb, "rectangle %.1f %.1f %.1f %.1f -fill %s -outline gray80", x, y, x+cellW, y+cellH, bg)
+		fg := "black"
+		if day.Equal(today) {
+			fg = "red"
+		}
+		s.item(sb, "text %.1f %.1f -anchor nw -fill %s -text %d", x+4, y+2, fg, day.Day())
+		idx := eventsIn(events, day, day.AddDate(0, 0, 1))
+		for n, ei := range idx {
+			if n >= maxBars {
+				s.item(sb, "text %.1f %.1f -anchor ne -fill gray40 -text %s", x+cellW-4, y+2,
+					tkstr("+"+strconv.Itoa(len(idx)-n)))
+				break
+			}
+			e := events[ei]
+			by := y + 18 + float64(n)*(schedBar+1)
+			last := !e.End.After(day.AddDate(0, 0, 1))
+			title := e.Title
+			if e.Start.Before(day) {
+				title = "… " + title
+			} else if e.Start.Hour() != 0 || e.Start.Minute() != 0 {
+				title = e.Start.Format("15:04") + " " + title
+			}
+			s.drawEvent(sb, eventSegment{ei, x + 2, by, x + cellW - 2, by + schedBar, last}, e, title)
+		}
+	}
+}
+
+func (s *Scheduler) drawWeek(sb *strings.Builder, w, h float64) {
+	from, _ := s.Period()
+	colW := (w - schedGutter) / 7
+	hours := s.toHour - s.fromHour
+	hourH := (h - schedHeader) / float64(hours)
+	today := dateOnly(time.Now())
+	events := s.shownEvents()
+	for i := 0; i <= hours; i++ {
+		y := schedHeader + hourH*float64(i)
+		s.item(sb, "line 0 %.1f %.1f %.1f -fill gray85", y, w, y)
+		if i < hours {
+			s.item(sb, "text %.1f %.1f -anchor ne -fill gray40 -font TkSmallCaptionFont -text %02d:00",
+				schedGutter-4, y+2, s.fromHour+i)
+		}
+	}
+	for col := 0; col < 7; col++ {
+		day := from.AddDate(0, 0, col)
+		x := schedGutter + colW*float64(col)
+		s.item(sb, "line %.1f 0 %.1f %.1f -fill gray75", x, x, h)
+		fg := "black"
+		if day.Equal(today) {
+			fg = "red"
+		}
+		s.item(sb, "text %.1f %.1f -anchor n -fill %s -text %s", x+colW/2, 3.0, fg,
+			tkstr(s.opts.Weekdays[day.Weekday()]+" "+strconv.Itoa(day.Day())))
+
+		// shown part of day, overlapping events are placed in lanes
+		dayFrom := day.Add(time.Duration(s.fromHour) * time.Hour)
+		dayTo := day.Add(time.Duration(s.toHour) * time.Hour)
+		idx := eventsIn(events, dayFrom, dayTo)
+		lanes := []time.Time{}
+		laneOf := map[int]int{}
+		for _, ei := range idx {
+			e := events[ei]
+			lane := len(lanes)
+			for l, end := range lanes {
+				if !end.After(e.Start) {
+					lane = l
+					break
+				}
+			}
+			if lane == len(lanes) {
+				lanes = append(lanes, e.End)
+			} else {
+				lanes[lane] = e.End
+			}
+			laneOf[ei] = lane
+		}
+		laneW := colW - 4
+		if len(lanes) > 1 {
+			laneW /= float64(len(lanes))
+		}
+		for _, ei := range idx {
+			e := events[ei]
+			start, end := e.Start, e.End
+			if start.Before(dayFrom) {
+				start = dayFrom
+			}
+			if end.After(dayTo) {
+				end = dayTo
+			}
+			y1 := schedHeader + start.Sub(dayFrom).Hours()*hourH
+			y2 := schedHeader + end.Sub(dayFrom).Hours()*hourH
+			if y2-y1 < schedBar {
+				y2 = y1 + schedBar
+			}
+			x1 := x + 2 + laneW*float64(laneOf[ei])
+			title := e.Start.Format("15:04") + " " + e.Title
+			last := !e.End.After(dayTo)
+			s.drawEvent(sb, eventSegment{ei, x1, y1, x1 + laneW, y2, last}, e, title)
+		}
+	}
+}
@@ -0,0 +1,406 @@
+package tg
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Appearance of tagged text in Text widget. Empty fields are not changed by tag.
+type Style struct {
+	Foreground string
+	Background string
+	Font       string // Tk font description (f.e. "Courier 10"), default is font of widget
+	Size       int    // font size in points
+	Bold       bool
+	Italic     bool
+	Underline  bool
+	Overstrike bool
+	Hidden     bool // do not show tagged text
+	Indent     int  // left margin in pixels
+}
+
+// Part of text between two Tk indexes ("line.column").
+type TextRange struct {
+	From string
+	To   string
+}
+
+const foundTag = "tgfound"
+
+// Run "f" which changes text even if Text is read only.
+func (t *Text) edit(f func()) {
+	if t.readOnly {
+		eval(t.id + " configure -state normal")
+		defer eval(t.id + " configure -state disabled")
+	}
+	f()
+}
+
+// Forbid or allow user to change text.
+func (t *Text) SetReadOnly(ro bool) {
+	t.readOnly = ro
+	if ro {
+		eval(t.id + " configure -state disabled")
+	} else {
+		eval(t.id + " configure -state normal")
+	}
+}
+
+func (t *Text) ReadOnly() bool {
+	return t.readOnly
+}
+
+// Return whole text without any changes.
+func (t *Text) getAll() string {
+	eval(t.id + " get 1.0 {end -1c}")
+	return result()
+}
+
+// Replace whole text with "text", undo history is cleared.
+func (t *Text) SetText(text string) {
+	t.edit(func() {
+		eval(t.id + " delete 1.0 end")
+		eval(t.id + " insert end " + tkstr(text))
+	})
+	t.ClearUndo()
+}
+
+func (t *Text) Undo() error {
+	return t.editCmd(t.id + " edit undo")
+}
+
+func (t *Text) Redo() error {
+	return t.editCmd(t.id + " edit redo")
+}
+
+func (t *Text) ClearUndo() {
+	eval(t.id + " edit reset")
+}
+
+func (t *Text) editCmd(tkcmd string) error {
+	var err error
+	t.edit(func() {
+		err = eval(tkcmd)
+	})
+	return err
+}
+
+// Call "f" on every change of text.
+func (t *Text) OnModified(f func()) {
+	eval(t.id + " edit modified 0")
+	t.Bind("<<Modified>>", "", func(s string) {
+		eval(t.id + " edit modified")
+		if result() != "1" {
+			return
+		}
+		// reset flag to get next <<Modified>> event
+		eval(t.id + " edit modified 0")
+		f()
+	})
+}
+
+// Define tag "name" with style "s" (tag is redefined if it exists).
+func (t *Text) Tag(name string, s Style) {
+	tkcmd := t.id + " tag configure " + tkstr(name) +
+		" -foreground " + tkstr(s.Foreground) +
+		" -background " + tkstr(s.Background) +
+		" -underline " + tagBool(s.Underline) +
+		" -overstrike " + tagBool(s.Overstrike) +
+		" -elide " + tagBool(s.Hidden)
+	if s.Indent > 0 {
+		tkcmd += " -lmargin1 " + strconv.Itoa(s.Indent) + " -lmargin2 " + strconv.Itoa(s.Indent)
+	} else {
+		tkcmd += " -lmargin1 {} -lmargin2 {}"
+	}
+	tkcmd += " -font " + t.tagFont(s)
+	eval(tkcmd)
+}
+
+// Return Tk font description for style "s".
+func (t *Text) tagFont(s Style) string {
+	if s.Font == "" && s.Size == 0 && !s.Bold && !s.Italic {
+		return "{}"
+	}
+	font := s.Font
+	if font == "" {
+		eval(t.id + " cget -font")
+		font = result()
+	}
+	if s.Size == 0 && !s.Bold && !s.Italic {
+		return tkstr(font)
+	}
+	eval("font actual " + tkstr(font))
+	opts := splitList(result())
+	for i := 0; i+1 < len(opts); i += 2 {
+		switch {
+		case opts[i] == "-size" && s.Size != 0:
+			opts[i+1] = strconv.Itoa(s.Size)
+		case opts[i] == "-weight" && s.Bold:
+			opts[i+1] = "bold"
+		case opts[i] == "-slant" && s.Italic:
+			opts[i+1] = "italic"
+		}
+	}
+	return tklist(opts)
+}
+
+// Tag option for boolean field of Style ("{}" means not set).
+func tagBool(b bool) string {
+	if b {
+		return "1"
+	}
+	return "{}"
+}
+
+// Apply tag "name" to text from index "from" to index "to".
+func (t *Text) ApplyTag(name string, from string, to string) {
+	eval(t.id + " tag add " + tkstr(name) + " " + tkstr(from) + " " + tkstr(to))
+}
+
+// Remove tag "name" from text between indexes ("1.0", "end" for whole text).
+func (t *Text) RemoveTag(name string, from string, to string) {
+	eval(t.id + " tag remove " + tkstr(name) + " " + tkstr(from) + " " + tkstr(to))
+}
+
+// Remove tag "name" from text and forget its style.
+func (t *Text) DeleteTag(name string) {
+	eval(t.id + " tag delete " + tkstr(name))
+}
+
+// Return ranges of text with tag "name".
+func (t *Text) TagRanges(name string) []TextRange {
+	eval(t.id + " tag ranges " + tkstr(name))
+	r := splitList(result())
+	res := []TextRange{}
+	for i := 0; i+1 < len(r); i += 2 {
+		res = append(res, TextRange{r[i], r[i+1]})
+	}
+	return res
+}
+
+// Call "cb" on "event" over text with tag "name", "params" as in Bind.
+func (t *Text) TagBind(name string, event string, params string, cb func(string)) {
+	if params != "" {
+		p := strings.Split(params, "")
+		params = " %" + strings.Join(p, " %")
+	}
+	eval(t.id + " tag bind " + tkstr(name) + " " + event + " {" + addCallbackCmd(cb) + params + "}")
+}
+
+// Insert "text" at index "pos" with tags (f.e. "end", "insert", "3.0").
+func (t *Text) InsertAt(pos string, text string, tags ...string) {
+	t.edit(func() {
+		eval(t.id + " insert " + tkstr(pos) + " " + tkstr(text) + tklist(tags))
+	})
+}
+
+// Delete text between indexes.
+func (t *Text) DeleteRange(from string, to string) {
+	t.edit(func() {
+		eval(t.id + " delete " + tkstr(from) + " " + tkstr(to))
+	})
+}
+
+// Return text between indexes.
+func (t *Text) GetRange(from string, to string) string {
+	eval(t.id + " get " + tkstr(from) + " " + tkstr(to))
+	return result()
+}
+
+// Return index in form "line.column" for any Tk index (f.e. "insert", "end").
+func (t *Text) Index(index string) string {
+	eval(t.id + " index " + tkstr(index))
+	return result()
+}
+
+// Return line (from 1) and column (from 0) of insert cursor.
+func (t *Text) Cursor() (int, int) {
+	return splitIndex(t.Index("insert"))
+}
+
+// Move insert cursor to "line" (from 1) and "col" (from 0) and show it.
+func (t *Text) SetCursor(line int, col int) {
+	eval(t.id + " mark set insert " + strconv.Itoa(line) + "." + strconv.Itoa(col))
+	eval(t.id + " see insert")
+}
+
+func splitIndex(index string) (int, int) {
+	p := strings.SplitN(index, ".", 2)
+	if len(p) != 2 {
+		return 0, 0
+	}
+	line, _ := strconv.Atoi(p[0])
+	col, _ := strconv.Atoi(p[1])
+	return line, col
+}
+
+// Return selected range, ok is false if there is no selection.
+func (t *Text) Selection() (TextRange, bool) {
+	r := t.TagRanges("sel")
+	if len(r) == 0 {
+		return TextRange{}, false
+	}
+	return TextRange{r[0].From, r[len(r)-1].To}, true
+}
+
+func (t *Text) SelectedText() string {
+	r, ok := t.Selection()
+	if !ok {
+		return ""
+	}
+	return t.GetRange(r.From, r.To)
+}
+
+// Select text between indexes and move cursor to the end of selection.
+func (t *Text) Select(from string, to string) {
+	eval(t.id + " tag remove sel 1.0 end")
+	eval(t.id + " tag add sel " + tkstr(from) + " " + tkstr(to))
+	eval(t.id + " mark set insert " + tkstr(to))
+	eval(t.id + " see insert")
+}
+
+func (t *Text) SelectAll() {
+	t.Select("1.0", "end -1c")
+}
+
+// Compile search pattern, "pattern" is plain text if "regex" is false.
+func searchRegexp(pattern string, regex bool, nocase bool) (*regexp.Regexp, error) {
+	if pattern == "" {
+		return nil, errors.New("Empty search pattern!")
+	}
+	if !regex {
+		pattern = regexp.QuoteMeta(pattern)
+	}
+	if nocase {
+		pattern = "(?i)" + pattern
+	}
+	return regexp.Compile(pattern)
+}
+
+// Convert byte offsets in "text" to Tk indexes, "offsets" must be sorted.
+func offsetsToIndexes(text string, offsets []int) []string {
+	res := make([]string, 0, len(offsets))
+	line, col, pos := 1, 0, 0
+	for _, off := range offsets {
+		for pos < off {
+			r, size := utf8.DecodeRuneInString(text[pos:])
+			if r == '\n' {
+				line++
+				col = 0
+			} else {
+				col++
+			}
+			pos += size
+		}
+		res = append(res, strconv.Itoa(line)+"."+strconv.Itoa(col))
+	}
+	return res
+}
+
+// Return ranges of all matches of "pattern" ("regex" for Go regular expression).
+func (t *Text) FindAll(pattern string, regex bool, nocase bool) ([]TextRange, error) {
+	re, err := searchRegexp(pattern, regex, nocase)
+	if err != nil {
+		return nil, err
+	}
+	text := t.getAll()
+	matches := re.FindAllStringIndex(text, -1)
+	offsets := make([]int, 0, len(matches)*2)
+	for _, m := range matches {
+		if m[0] == m[1] {
+			continue
+		}
+		offsets = append(offsets, m[0], m[1])
+	}
+	indexes := offsetsToIndexes(text, offsets)
+	res := make([]TextRange, 0, len(indexes)/2)
+	for i := 0; i+1 < len(indexes); i += 2 {
+		res = append(res, TextRange{indexes[i], indexes[i+1]})
+	}
+	return res, nil
+}
+
+// Find next match after insert cursor (searching from the begin of text
+// if there are no matches after cursor), select and show it.
+func (t *Text) FindNext(pattern string, regex bool, nocase bool) (TextRange, bool, error) {
+	found, err := t.FindAll(pattern, regex, nocase)
+	if err != nil || len(found) == 0 {
+		return TextRange{}, false, err
+	}
+	next := found[0]
+	for _, r := range found {
+		eval(t.id + " compare " + r.From + " >= insert")
+		if result() == "1" {
+			next = r
+			break
+		}
+	}
+	t.Select(next.From, next.To)
+	return next, true, nil
+}
+
+// Replace next (after cursor) or all matches of "pattern" with "repl"
+// ($1 in "repl" is replaced with submatch for regex), return count of replacements.
+func (t *Text) Replace(pattern string, repl string, regex bool, nocase bool, all bool) (int, error) {
+	re, err := searchRegexp(pattern, regex, nocase)
+	if err != nil {
+		return 0, err
+	}
+	text := t.getAll()
+	matches := re.FindAllStringSubmatchIndex(text, -1)
+	if len(matches) == 0 {
+		return 0, nil
+	}
+	offsets := make([]int, 0, len(matches)*2)
+	for _, m := range matches {
+		offsets = append(offsets, m[0], m[1])
+	}
+	indexes := offsetsToIndexes(text, offsets)
+	first := 0
+	if !all {
+		for i := range matches {
+			eval(t.id + " compare " + indexes[i*2] + " >= insert")
+			if result() == "1" {
+				first = i
+				break
+			}
+		}
+		matches = matches[first : first+1]
+	}
+	eval(t.id + " edit separator")
+	// replace from the end, so indexes of previous matches are not changed
+	for i := len(matches) - 1; i >= 0; i-- {
+		r := repl
+		if regex {
+			r = string(re.ExpandString(nil, repl, text, matches[i]))
+		}
+		from, to := indexes[(first+i)*2], indexes[(first+i)*2+1]
+		t.edit(func() {
+			eval(t.id + " replace " + from + " " + to + " " + tkstr(r))
+		})
+	}
+	eval(t.id + " edit separator")
+	return len(matches), nil
+}
+
+// Highlight all matches of "pattern", return count of matches.
+func (t *Text) HighlightAll(pattern string, regex bool, nocase bool) (int, error) {
+	t.ClearHighlight()
+	found, err := t.FindAll(pattern, regex, nocase)
+	if err != nil {
+		return 0, err
+	}
+	eval(t.id + " tag configure " + foundTag + " -background yellow")
+	eval(t.id + " tag raise " + foundTag)
+	for _, r := range found {
+		t.ApplyTag(foundTag, r.From, r.To)
+	}
+	return len(found), nil
+}
+
+func (t *Text) ClearHighlight() {
+	t.RemoveTag(foundTag, "1.0", "end")
+}
@@ -4,9 +4,12 @@ import (
 	"errors"
 	"fmt"
 	"image"
+	"runtime"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
+	"unsafe"
 )
 
 /*
@@ -15,6 +18,7 @@ import (
 #cgo windows CFLAGS: -IC:/Tcl/include/
 #cgo windows LDFLAGS: C:/Tcl/bin/tcl86.dll C:/Tcl/bin/tk86.dll
 
+#include <stdlib.h>
 #include <tk.h>
 
 extern void cmdHandler(unsigned int cmdIndex, char* ss);
@@ -25,6 +29,18 @@ static inline int CmdCallback(ClientData clientData, Tcl_Interp *interp, int arg
 static inline void RegisterCmd(Tcl_Interp *interp, char *cmdName, unsigned int cmdIndex) {
    Tcl_CreateCommand(interp, cmdName, CmdCallback, (void *)cmdIndex, (Tcl_CmdDeleteProc *)NULL );
 }
+
+extern void asyncHandler(void);
+static inline int AsyncEventProc(Tcl_Event *ev, int flags) {
+   asyncHandler();
+   return 1;
+}
+static inline void QueueAsync(Tcl_ThreadId thread) {
+   Tcl_Event *ev = (Tcl_Event *)ckalloc(sizeof(Tcl_Event));
+   ev->proc = AsyncEventProc;
+   Tcl_ThreadQueueEvent(thread, ev, TCL_QUEUE_TAIL);
+   Tcl_ThreadAlert(thread);
+}
 */
 import "C"
 
@@ -49,10 +65,22 @@ var (
 	genNextId    func() string
 	widgets      map[string]interface{}
 	rt           *root
+	mainThread   C.Tcl_ThreadId
+	asyncMu      sync.Mutex
+	asyncFuncs   []func()
 )
 
+// Tcl interpretator must be used only from thread where it was created.
+func init() {
+	runtime.LockOSThread()
+}
+
+var tkEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, `[`, `\[`, `]`, `\]`, `$`, `\$`)
+
+// Return "s" as quoted Tcl word, backslashes, quotes, brackets and "$" are escaped,
+// so text is passed to Tk as is (without substitution of commands and variables).
 func tkstr(s string) string {
-	return "\"" + s + "\""
+	return "\"" + tkEscaper.Replace(s) + "\""
 }
 
 func tklist(l []string) string {
@@ -78,6 +106,26 @@ func cmdHandler(cmdIndex C.uint, ss *C.char) {
 	callbackCmds[cmdIndex](C.GoString(ss))
 }
 
+//export asyncHandler
+func asyncHandler() {
+	asyncMu.Lock()
+	funcs := asyncFuncs
+	asyncFuncs = nil
+	asyncMu.Unlock()
+	for _, f := range funcs {
+		f()
+	}
+}
+
+// Run "f" in main loop. It's safe to call Async from any goroutine after InitRoot,
+// all widgets must be changed from other goroutines only with Async.
+func Async(f func()) {
+	asyncMu.Lock()
+	asyncFuncs = append(asyncFuncs, f)
+	asyncMu.Unlock()
+	C.QueueAsync(mainThread)
+}
+
 func addCallbackCmd(command func(string)) string {
 	name := genNextId()
 	callbackCmds = append(callbackCmds, command)
@@ -111,6 +159,23 @@ func result() string {
 	return C.GoString(C.Tcl_GetStringResult(interp))
 }
 
+// Split Tcl list "s" into elements.
+func splitList(s string) []string {
+	var argc C.int
+	var argv **C.char
+	cs := C.CString(s)
+	defer C.free(unsafe.Pointer(cs))
+	if C.Tcl_SplitList(interp, cs, &argc, &argv) != C.TCL_OK {
+		return []string{}
+	}
+	defer C.Tcl_Free((*C.char)(unsafe.Pointer(argv)))
+	res := make([]string, 0, int(argc))
+	for _, p := range unsafe.Slice(argv, int(argc)) {
+		res = append(res, C.GoString(p))
+	}
+	return res
+}
+
 var table_sort = `proc sort_clients_table {tree col direction} {
     # Build something we can sort
     set data {}
@@ -134,6 +199,7 @@ var table_sort = `proc sort_clients_table {tree col direction} {
 // Initialise Tcl and Tk interpretators, Id generator, callback commands list.
 func InitRoot(title string, flags uint) (Container, error) {
 	interp = C.Tcl_CreateInterp()
+	mainThread = C.Tcl_GetCurrentThread()
 
 	if C.Tcl_Init(interp) != C.TCL_OK {
 		return nil, errors.New(C.GoString(C.Tcl_GetStringResult(interp)))
@@ -671,7 +737,15 @@ func (b *Check) IfCheck(command func(string)) {
 // ======== Entry =================
 type Entry struct {
 	widget
-	text string
+	text          string
+	mask          *inputMask
+	placeholder   string
+	clearButton   bool
+	onChange      func(text string)
+	onFocusIn     func()
+	onFocusOut    func()
+	textVar       string
+	changePending bool
 }
 
 func NewEntry(text string, flags uint) *Entry {
@@ -680,24 +754,40 @@ func NewEntry(text string, flags uint) *Entry {
 		initParam += "-show *"
 	}
 	w := widget{"", "ttk::entry", initParam, flags}
-	e := Entry{w, text}
+	e := Entry{widget: w, text: text}
 	return &e
 }
 
 func (e *Entry) create(parentId string) (string, uint) {
 	id, flags := e.widget.create(parentId)
 	widgets[id] = e
-	eval(id + " insert 0 " + tkstr(e.text))
+	if e.mask != nil {
+		e.bindMask()
+		e.SetText(e.text)
+	} else {
+		eval(id + " insert 0 " + tkstr(e.text))
+	}
+	e.traceText()
 	return id, flags
 }
 
 //func (e *Entry) ById(id string) {}
 
 func (e *Entry) Clear() {
+	if e.mask != nil {
+		e.mask.raw = nil
+		e.showMask(0)
+		return
+	}
 	eval(e.id + " delete 0 end")
 }
 
 func (e *Entry) SetText(text string) {
+	if e.mask != nil {
+		e.mask.raw = e.mask.normalize(text, 0)
+		e.showMask(len(e.mask.raw))
+		return
+	}
 	e.Clear()
 	eval(e.id + " insert 0 " + tkstr(text))
 }
@@ -707,6 +797,7 @@ func (e *Entry) GetText() string {
 	return result()
 }
 
+// Deprecated: replaces only first comma, use NumberEntry for numbers.
 func (e *Entry) GetDotText() string {
 	return strings.Replace(e.GetText(), ",", ".", 1)
 }
@@ -843,6 +934,37 @@ func (l *Listbox) SetSelectionValue(s string) {
 	l.SetSelection(i)
 }
 
+// Return items shown in Listbox.
+func (l *Listbox) Items() []string {
+	eval("set " + l.listvar)
+	return splitList(result())
+}
+
+// Insert "val" before item "index" (-1 for the end).
+func (l *Listbox) Insert(index int, val string) {
+	pos := "end"
+	if index >= 0 {
+		pos = strconv.Itoa(index)
+	}
+	eval(l.id + " insert " + pos + " " + tkstr(val))
+	l.list = l.Items()
+}
+
+func (l *Listbox) Delete(index int) {
+	eval(l.id + " delete " + strconv.Itoa(index))
+	l.list = l.Items()
+}
+
+// Move item "from" so it becomes item "to".
+func (l *Listbox) Move(from int, to int) {
+	items := l.Items()
+	if from < 0 || from >= len(items) || from == to {
+		return
+	}
+	l.Delete(from)
+	l.Insert(to, items[from])
+}
+
 // ======== Tree =================
 type Tree struct {
 	widget
@@ -927,26 +1049,39 @@ func (t *Tree) Columns(columns string) {
 	eval(t.id + " configure -columns " + tkstr(columns))
 }
 
+// Return node "id" as Tcl word, root node can be passed as "" or "{}".
+func treeNode(id string) string {
+	return tkstr(treeId(id))
+}
+
+// Return "id" of node with root as "".
+func treeId(id string) string {
+	if id == "{}" {
+		return ""
+	}
+	return id
+}
+
 // .2.tr insert 1 end -id 3 -text ccc -values [list sss 222 333]
 func (t *Tree) Insert(parent string, id string, item string, data []string) {
 	if data != nil {
-		eval(t.id + " insert " + parent + " end -id " + id + " -text " + tkstr(item) + " -values " + tklist(data) + " -open true")
+		eval(t.id + " insert " + treeNode(parent) + " end -id " + id + " -text " + tkstr(item) + " -values " + tklist(data) + " -open true")
 	} else {
-		eval(t.id + " insert " + parent + " end -id " + id + " -text " + tkstr(item) + " -open true")
+		eval(t.id + " insert " + treeNode(parent) + " end -id " + id + " -text " + tkstr(item) + " -open true")
 	}
 }
 
 // insert not open
 func (t *Tree) InsertNO(parent string, id string, item string, data []string) {
 	if data != nil {
-		eval(t.id + " insert " + parent + " end -id " + id + " -text " + tkstr(item) + " -values " + tklist(data))
+		eval(t.id + " insert " + treeNode(parent) + " end -id " + id + " -text " + tkstr(item) + " -values " + tklist(data))
 	} else {
-		eval(t.id + " insert " + parent + " end -id " + id + " -text " + tkstr(item))
+		eval(t.id + " insert " + treeNode(parent) + " end -id " + id + " -text " + tkstr(item))
 	}
 }
 
 func (t *Tree) InsertData(parent string, data []string) {
-	eval(t.id + " insert " + parent + " end -values " + tklist(data))
+	eval(t.id + " insert " + treeNode(parent) + " end -values " + tklist(data))
 }
 
 func (t *Tree) Clear() {
@@ -966,23 +1101,149 @@ func (t *Tree) SetWidth(width int) {
 	eval(t.id + " column #0 -width " + strconv.Itoa(width))
 }
 
+// Return ids of children of node "id" ("" for root).
+func (t *Tree) Children(id string) []string {
+	if err := eval(t.id + " children " + treeNode(id)); err != nil {
+		return []string{}
+	}
+	return splitList(result())
+}
+
+// Return id of parent of node "id" ("" for top level nodes).
+func (t *Tree) Parent(id string) string {
+	if err := eval(t.id + " parent " + tkstr(id)); err != nil {
+		return ""
+	}
+	return result()
+}
+
+// Return ids of nodes from top level node down to node "id".
+func (t *Tree) Path(id string) []string {
+	path := []string{}
+	for id != "" && t.Exists(id) {
+		path = append([]string{id}, path...)
+		id = t.Parent(id)
+	}
+	return path
+}
+
+// Return text of node "id".
+func (t *Tree) Text(id string) string {
+	if err := eval(t.id + " item " + tkstr(id) + " -text"); err != nil {
+		return ""
+	}
+	return result()
+}
+
+// Return values of node "id".
+func (t *Tree) Values(id string) []string {
+	if err := eval(t.id + " item " + tkstr(id) + " -values"); err != nil {
+		return []string{}
+	}
+	return splitList(result())
+}
+
+func (t *Tree) Exists(id string) bool {
+	if err := eval(t.id + " exists " + tkstr(id)); err != nil {
+		return false
+	}
+	return result() == "1"
+}
+
+// Move node "id" to "newParent" at position "index" (-1 for the end).
+func (t *Tree) Move(id string, newParent string, index int) error {
+	for _, p := range t.Path(newParent) {
+		if p == id {
+			return errors.New("Can't move item " + id + " into itself!")
+		}
+	}
+	pos := "end"
+	if index >= 0 {
+		pos = strconv.Itoa(index)
+	}
+	return eval(t.id + " move " + tkstr(id) + " " + treeNode(newParent) + " " + pos)
+}
+
+// Change text and values of node "id" (values are not changed if nil).
+func (t *Tree) Update(id string, text string, values []string) error {
+	tkcmd := t.id + " item " + tkstr(id) + " -text " + tkstr(text)
+	if values != nil {
+		tkcmd += " -values " + tklist(values)
+	}
+	return eval(tkcmd)
+}
+
+// Delete node "id" with all its children.
+func (t *Tree) Delete(id string) {
+	eval(t.id + " delete " + tkstr(id))
+}
+
+func (t *Tree) Expand(id string, open bool) {
+	eval(t.id + " item " + tkstr(id) + " -open " + strconv.FormatBool(open))
+}
+
+func (t *Tree) ExpandAll() {
+	t.walk("", func(id string) bool {
+		t.Expand(id, true)
+		return false
+	})
+}
+
+func (t *Tree) CollapseAll() {
+	t.walk("", func(id string) bool {
+		t.Expand(id, false)
+		return false
+	})
+}
+
+// Return ids of all nodes for which "f" returns true (depth first order).
+func (t *Tree) Find(f func(id string, text string, values []string) bool) []string {
+	found := []string{}
+	t.walk("", func(id string) bool {
+		if f(id, t.Text(id), t.Values(id)) {
+			found = append(found, id)
+		}
+		return false
+	})
+	return found
+}
+
+// Call "f" for every node under "parent" in depth first order, stop if "f" returns true.
+func (t *Tree) walk(parent string, f func(id string) bool) bool {
+	for _, c := range t.Children(parent) {
+		if f(c) || t.walk(c, f) {
+			return true
+		}
+	}
+	return false
+}
+
 // ======== Image =================
 type Image struct {
 	widget
 	imageFile string
+	img       image.Image // image uploaded when widget is created
+	owned     bool        // photo is created by widget and deleted with it
 }
 
 func NewImage(imageFile string, flags uint) *Image {
 	initParam := ""
 	w := widget{"", "label", initParam, flags}
-	i := Image{w, imageFile}
+	i := Image{widget: w, imageFile: imageFile}
 	return &i
 }
 
 func (i *Image) create(parentId string) (string, uint) {
 	id, flags := i.widget.create(parentId)
 	widgets[id] = i
+	if i.img != nil {
+		i.SetImage(i.img)
+		i.img = nil
+	}
 	eval(id + " configure -image " + tkstr(i.imageFile))
+	eval("bind " + id + " <Destroy> {+" + addCallbackCmd(func(s string) {
+		i.deletePhoto()
+	}) + "}")
 	//	eval(id + " configure -image [image create photo -file " + tkstr(i.imageFile) + "]")
 	/*
 		bs, err := ioutil.ReadFile(i.imageFile)
@@ -998,7 +1259,10 @@ func (i *Image) create(parentId string) (string, uint) {
 // ======== Combobox =================
 type Combobox struct {
 	widget
-	list []string
+	list  []string
+	keys  []string
+	shown []int // indexes of items shown in filtered list, nil if all items are shown
+	mode  int
 }
 
 func NewCombobox(list []string, flags uint) *Combobox {
@@ -1007,7 +1271,7 @@ func NewCombobox(list []string, flags uint) *Combobox {
 		initParam += " -values " + tklist(list)
 	}
 	w := widget{"", "ttk::combobox", initParam, flags}
-	c := Combobox{w, list}
+	c := Combobox{widget: w, list: list, keys: list}
 	return &c
 }
 
@@ -1018,15 +1282,23 @@ func (c *Combobox) create(parentId string) (string, uint) {
 	if len(c.list) > 0 {
 		eval(id + " current 0")
 	}
+	c.Bind("<KeyRelease>", "K", c.keyRelease)
+	c.SetMode(c.mode)
 	return id, flags
 }
 
 func (c *Combobox) Update(list []string) {
+	c.list = list
+	c.keys = list
+	c.shown = nil
 	eval(c.id + " configure -values " + tklist(list))
 	eval(c.id + " current 0")
 }
 
 func (c *Combobox) SetSelection(i int) {
+	if c.shown != nil {
+		c.filter("")
+	}
 	eval(c.id + " current " + strconv.Itoa(i))
 	eval("event generate " + c.id + " <<ComboboxSelected>>")
 }
@@ -1056,7 +1328,7 @@ func (c *Combobox) GetSelection() (int, string) {
 	}
 	val := result()
 	n, _ := strconv.Atoi(sel)
-	return n, val
+	return c.itemIndex(n), val
 }
 
 func (c *Combobox) IfSelect(f func(index int, val string)) {
@@ -1233,15 +1505,16 @@ func (t *Tree) InsertData(parent string, data []string) {
 // ======== Text =================
 type Text struct {
 	widget
-	b    *Box
-	text string
+	b        *Box
+	text     string
+	readOnly bool
 }
 
 func NewText(text string, flags uint) *Text {
-	initParam := " -wrap word -width 20 -height 5"
+	initParam := " -wrap word -width 20 -height 5 -undo 1"
 	w := widget{"", "text", initParam, flags}
 	b := NewBox(flags | Expand)
-	t := Text{w, b, text}
+	t := Text{w, b, text, false}
 	return &t
 }
 
@@ -1284,15 +1557,21 @@ func (t *Text) create(parentId string) (string, uint) {
 }
 
 func (t *Text) Clear() {
-	eval(t.id + " delete 1.0 end")
+	t.edit(func() {
+		eval(t.id + " delete 1.0 end")
+	})
 }
 
 func (t *Text) Insert(text string) {
-	eval(t.id + " insert 1.0 " + tkstr(text))
+	t.edit(func() {
+		eval(t.id + " insert 1.0 " + tkstr(text))
+	})
 }
 
 func (t *Text) Add(text string) {
-	eval(t.id + " insert end " + tkstr(text))
+	t.edit(func() {
+		eval(t.id + " insert end " + tkstr(text))
+	})
 }
 
 func (t *Text) Get() string {
@@ -1303,25 +1582,25 @@ func (t *Text) Get() string {
 // ======== Calendar =================
 type Calendar struct {
 	Box
+	dateLimits
 	lb            *Label
 	bt            *Button
 	ltextvariable string
-	selWidgetId   string
-	selWidgetBg   string
-	lbtime        *Label
-	dates         []*Label
 	ltime         time.Time
 	day           int
 	tl            *Dialog
+	grid          *monthGrid
+	opts          CalendarOptions
+	bc            *Button
+	bs            *Button
+	onChange      func(date time.Time)
 }
 
 func NewCalendar(flags uint) *Calendar {
 	//	initParam := ""
 
 	b := NewBox(flags | Horizontal)
-	t := Calendar{*b, NewLabel("", 0), NewButton(">", 0),
-		"", "", "", NewLabel("", 0), []*Label{}, time.Time{}, 0, nil,
-	}
+	t := Calendar{Box: *b, lb: NewLabel("", 0), bt: NewButton(">", 0), opts: calendarOptions}
 	return &t
 }
 
@@ -1330,7 +1609,10 @@ func (t *Calendar) create(parentId string) (string, uint) {
 	widgets[id] = t
 	t.Add(t.lb, t.bt)
 	tm := time.Now()
-	ltime := tm.Format("02.01.2006")
+	if !t.ltime.IsZero() {
+		tm = t.ltime
+	}
+	ltime := tm.Format(t.opts.Layout)
 	t.ltextvariable = genNextId()
 	SetVar(t.ltextvariable, ltime)
 	eval(t.lb.id + " configure -textvariable " + t.ltextvariable)
@@ -1338,86 +1620,68 @@ func (t *Calendar) create(parentId string) (string, uint) {
 	return id, flags
 }
 
-func (t *Calendar) Set(s string) {
-	t.lb.SetText(s)
-	t.ltime, _ = time.Parse("02.01.2006", s)
+// Set names, labels and layouts, shown date is converted to new layout.
+func (t *Calendar) SetOptions(o CalendarOptions) {
+	old := t.opts
+	t.opts = o
+	if t.id == "" {
+		return
+	}
+	if tm, err := old.parse(t.lb.Text()); err == nil {
+		SetVar(t.ltextvariable, tm.Format(o.Layout))
+	}
+	if t.tl != nil {
+		eval("wm title " + t.tl.id + " " + tkstr(o.Title))
+		t.bc.SetText(o.Cancel)
+		t.bs.SetText(o.Accept)
+		t.makeMonth()
+	}
 }
 
-func (t *Calendar) call(s string) {
-	t.ltime, _ = time.Parse("02.01.2006", t.lb.Text())
-	if len(t.dates) < 1 {
-
-		//t.dates = []*Label{}
-		t.tl = NewDialog("Оберіть дату", NotModal)
-		bx := NewBox(Horizontal)
-		grid := NewGrid(7, Expand)
-		bbx := NewBox(Horizontal)
-		t.tl.Add(t.lbtime, bx, grid, bbx)
-
-		bDecYear := NewButton("<<", 0)
-		bDecMonth := NewButton("<", 0)
-		bIncMonth := NewButton(">", 0)
-		bIncYear := NewButton(">>", 0)
-
-		bx.Add(bDecYear, bDecMonth, bIncMonth, bIncYear)
-
-		bDecYear.Width(3)
-		bDecMonth.Width(2)
-		bIncMonth.Width(2)
-		bIncYear.Width(3)
+// Set date from text in Layout or one of ParseLayouts of options.
+func (t *Calendar) Set(s string) error {
+	tm, err := t.opts.parse(s)
+	if err != nil {
+		return err
+	}
+	return t.SetValue(tm)
+}
 
-		bDecYear.IfPressed(func(s string) {
-			year, month, day := t.ltime.Date()
-			t.ltime = time.Date(year-1, month, day, 0, 0, 0, 0, time.Local)
-			//t.ltime = t.ltime.Add(-time.Hour * 365 * 24)
-			t.makeMonth()
-		})
-		bDecMonth.IfPressed(func(s string) {
-			year, month, day := t.ltime.Date()
-			month = time.Month(int(month) - 1)
-			t.ltime = time.Date(year, month, day, 0, 0, 0, 0, time.Local)
-			//t.ltime = t.ltime.Add(-time.Hour * 31 * 24)
-			t.makeMonth()
-		})
-		bIncMonth.IfPressed(func(s string) {
-			year, month, day := t.ltime.Date()
-			month = time.Month(int(month) + 1)
-			t.ltime = time.Date(year, month, day, 0, 0, 0, 0, time.Local)
-			//t.ltime = t.ltime.Add(time.Hour * 31 * 24)
-			t.makeMonth()
-		})
-		bIncYear.IfPressed(func(s string) {
-			year, month, day := t.ltime.Date()
-			t.ltime = time.Date(year+1, month, day, 0, 0, 0, 0, time.Local)
-			//t.ltime = t.ltime.Add(time.Hour * 365 * 24)
-			t.makeMonth()
+func (t *Calendar) call(s string) {
+	t.ltime = t.Value()
+	if t.ltime.IsZero() {
+		t.ltime = dateOnly(time.Now())
+	}
+	t.day = t.ltime.Day()
+	if t.tl == nil {
+		t.tl = NewDialog(t.opts.Title, NotModal)
+		t.grid = newMonthGrid(t.shiftMonth, func(date time.Time) {
+			if t.Allowed(date) {
+				t.day = date.Day()
+				t.makeMonth()
+			}
 		})
-
-		for _, v := range []string{"Пн", "Вт", "Ср", "Чт", "Пт", "Сб", "Нд"} {
-			grid.Add(NewLabel(v, 0))
-		}
-		for i := 0; i < 42; i++ {
-			l := NewLabel(" ", 0)
-			grid.Add(l)
-			l.Color("black", "gray")
-			eval(l.id + " configure -anchor center")
-			l.Bind("<Button-1>", "W", t.makeSelect)
-			t.dates = append(t.dates, l)
-		}
-
-		bc := NewButton("Відмінити", 0)
-		bs := NewButton("Прийняти", 0)
-		bbx.Add(bc, bs)
-		bc.Width(-1)
-		bs.Width(-1)
-		bc.IfPressed(func(s string) {
+		bbx := NewBox(Horizontal)
+		t.tl.Add(t.grid, bbx)
+
+		t.bc = NewButton(t.opts.Cancel, 0)
+		t.bs = NewButton(t.opts.Accept, 0)
+		bbx.Add(t.bc, t.bs)
+		t.bc.Width(-1)
+		t.bs.Width(-1)
+		t.bc.IfPressed(func(s string) {
 			t.tl.Destroy()
 		})
-		bs.IfPressed(func(s string) {
+		t.bs.IfPressed(func(s string) {
 			year, month, _ := t.ltime.Date()
 			dt := time.Date(year, month, t.day, 0, 0, 0, 0, time.Local)
-			SetVar(t.ltextvariable, dt.Format("02.01.2006"))
+			if t.day == 0 || t.SetValue(dt) != nil {
+				return
+			}
 			t.tl.Destroy()
+			if t.onChange != nil {
+				t.onChange(dt)
+			}
 		})
 	} else {
 		eval("wm deiconify " + t.tl.id)
@@ -1426,43 +1690,17 @@ func (t *Calendar) call(s string) {
 	t.tl.Call()
 }
 
+// Show month of dialog, current date is yellow and selected one is red.
 func (t *Calendar) makeMonth() {
-	t.lbtime.SetText(t.ltime.Format("01.2006"))
-	year, month, day := t.ltime.Date()
-	start := time.Date(year, month, 1, 0, 0, 0, 0, time.Local)
-	wday := start.Weekday()
-	thisMonth := start.Month()
-	for i := 0; i < 42; i++ {
-		if i < 7 && i < int(wday)-1 {
-			t.dates[i].SetText(" ")
-			t.dates[i].Color("", "gray")
-			continue
+	t.grid.render(t.ltime, t.opts, &t.dateLimits, func(date time.Time) (string, string) {
+		switch {
+		case date.Day() == t.day && t.day != t.ltime.Day():
+			return "", "red"
+		case date.Day() == t.ltime.Day():
+			return "", "yellow"
 		}
-		if thisMonth == start.Month() {
-			t.dates[i].SetText(strconv.Itoa(start.Day()))
-			t.dates[i].Color("", "gray")
-			if day == start.Day() {
-				t.dates[i].Color("", "yellow")
-			}
-			start = start.Add(time.Hour * 24)
-			continue
-		}
-		t.dates[i].SetText(" ")
-	}
-
-}
-
-func (t *Calendar) makeSelect(s string) {
-	s = strings.Split(s, " ")[1]
-	if t.selWidgetId != "" {
-		eval(t.selWidgetId + " configure -background " + t.selWidgetBg)
-	}
-	t.selWidgetId = s
-	eval(s + " cget -background")
-	t.selWidgetBg = result()
-	eval(s + " configure -background red")
-	eval(s + " cget -text")
-	t.day, _ = strconv.Atoi(result())
+		return "", ""
+	})
 }
 
 func (t *Calendar) GetText() string {
@@ -1470,34 +1708,31 @@ func (t *Calendar) GetText() string {
 }
 
 func (t *Calendar) Today() {
-	dt := time.Now()
-	SetVar(t.ltextvariable, dt.Format("02.01.2006"))
+	t.SetValue(time.Now())
 }
 
+// Upload "img" to Tk photo "name", photo is created if there is no such photo.
 func Upload_image(name string, img image.Image) error {
-	//var buf bytes.Buffer
-	//err := sprintf(&buf, "image create photo %{}", name)
-
-	nrgba, ok := img.(*image.NRGBA)
-	if !ok {
-		// let's do it slowpoke
-		bounds := img.Bounds()
-		nrgba = image.NewNRGBA(bounds)
-		for x := 0; x < bounds.Max.X; x++ {
-			for y := 0; y < bounds.Max.Y; y++ {
-				nrgba.Set(x, y, img.At(x, y))
-			}
-		}
+	b := img.Bounds()
+	return UploadImageRegion(name, img, b, 0, 0)
+}
+
+// Put region "r" of "img" to Tk photo "name" at position "x", "y" (f.e. to update only changed part).
+// Photo is created if there is no such photo.
+func UploadImageRegion(name string, img image.Image, r image.Rectangle, x int, y int) error {
+	r = r.Intersect(img.Bounds())
+	if r.Empty() {
+		return nil
 	}
+	pix, stride := nrgbaPixels(img, r)
 
 	cname := C.CString(name)
-	//	defer C.free(unsafe.Pointer(cname))
+	defer C.free(unsafe.Pointer(cname))
 
 	handle := C.Tk_FindPhoto(interp, cname)
 	if handle == nil {
 		err := eval("image create photo " + name)
 		if err != nil {
-			fmt.Println("RRRR")
 			return err
 		}
 		handle = C.Tk_FindPhoto(interp, cname)
@@ -1506,23 +1741,47 @@ func Upload_image(name string, img image.Image) error {
 		}
 	}
 
-	imgdata := C.CBytes(nrgba.Pix)
-	//	defer C.free(imgdata)
+	imgdata := C.CBytes(pix)
+	defer C.free(imgdata)
 
 	block := C.Tk_PhotoImageBlock{
 		(*C.uchar)(imgdata),
-		C.int(nrgba.Rect.Max.X),
-		C.int(nrgba.Rect.Max.Y),
-		C.int(nrgba.Stride),
+		C.int(r.Dx()),
+		C.int(r.Dy()),
+		C.int(stride),
 		4,
 		[...]C.int{0, 1, 2, 3},
 	}
 
-	status := C.Tk_PhotoPutBlock(interp, handle, &block, 0, 0,
-		C.int(nrgba.Rect.Max.X), C.int(nrgba.Rect.Max.Y),
+	status := C.Tk_PhotoPutBlock(interp, handle, &block, C.int(x), C.int(y),
+		C.int(r.Dx()), C.int(r.Dy()),
 		C.TK_PHOTO_COMPOSITE_SET)
 	if status != C.TCL_OK {
 		return errors.New(C.GoString(C.Tcl_GetStringResult(interp)))
 	}
 	return nil
 }
+
+// Return copy of pixels of Tk photo "name" (f.e. to save shown image).
+func PhotoToImage(name string) (image.Image, error) {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+
+	handle := C.Tk_FindPhoto(interp, cname)
+	if handle == nil {
+		return nil, errors.New("There is no photo " + name)
+	}
+	var block C.Tk_PhotoImageBlock
+	C.Tk_PhotoGetImage(handle, &block)
+	w, h := int(block.width), int(block.height)
+	pitch, size := int(block.pitch), int(block.pixelSize)
+	if w <= 0 || h <= 0 {
+		return image.NewNRGBA(image.Rect(0, 0, 0, 0)), nil
+	}
+	pix := C.GoBytes(unsafe.Pointer(block.pixelPtr), C.int((h-1)*pitch+w*size))
+	offset := [4]int{}
+	for i := range offset {
+		offset[i] = int(block.offset[i])
+	}
+	return blockToImage(pix, w, h, pitch, size, offset), nil
+}
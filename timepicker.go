@@ -0,0 +1,187 @@
+package tg
+
+import (
+	"errors"
+	"strconv"
+	"time"
+)
+
+// ======== TimePicker =================
+type TimePicker struct {
+	Box
+	hour12  bool
+	seconds bool
+	hours   int
+	minutes int
+	secs    int
+	spins   []string // ids of spinboxes for hours, minutes and seconds
+	ampm    string   // id of AM/PM combobox
+}
+
+// Return pointer to new TimePicker with spinboxes for hours, minutes and "seconds" (if true).
+// Hours are shown from 1 to 12 with AM/PM if "hour12" is true.
+func NewTimePicker(hour12 bool, seconds bool, flags uint) *TimePicker {
+	b := NewBox(flags | Horizontal)
+	t := TimePicker{Box: *b, hour12: hour12, seconds: seconds}
+	return &t
+}
+
+func (t *TimePicker) create(parentId string) (string, uint) {
+	id, flags := t.widget.create(parentId)
+	widgets[id] = t
+	from, to := "0", "23"
+	if t.hour12 {
+		from, to = "1", "12"
+	}
+	limits := [][2]string{{from, to}, {"0", "59"}, {"0", "59"}}
+	count := 2
+	if t.seconds {
+		count = 3
+	}
+	t.spins = nil
+	for i := 0; i < count; i++ {
+		if i > 0 {
+			l := id + "." + genNextId()
+			eval("ttk::label " + l + " -text :")
+			eval("pack " + l + " -side left")
+		}
+		sb := id + "." + genNextId()
+		eval("ttk::spinbox " + sb + " -from " + limits[i][0] + " -to " + limits[i][1] +
+			" -increment 1 -wrap 1 -width 3 -format %02.0f -justify right" +
+			" -validate key -validatecommand {expr {[string is digit %P] && [string length %P] <= 2}}")
+		eval("pack " + sb + " -side left")
+		t.spins = append(t.spins, sb)
+	}
+	if t.hour12 {
+		t.ampm = id + "." + genNextId()
+		eval("ttk::combobox " + t.ampm + " -values {AM PM} -state readonly -width 3")
+		eval("pack " + t.ampm + " -side left -padx 2")
+	}
+	t.show()
+	return id, flags
+}
+
+// Return hours (0-23), minutes and seconds, error is returned for wrong text in spinbox.
+func (t *TimePicker) Clock() (int, int, int, error) {
+	if t.id == "" {
+		return t.hours, t.minutes, t.secs, nil
+	}
+	values := []int{0, 0, 0}
+	limits := []int{23, 59, 59}
+	if t.hour12 {
+		limits[0] = 12
+	}
+	for i, sb := range t.spins {
+		eval(sb + " get")
+		v, err := strconv.Atoi(result())
+		if err != nil || v < 0 || v > limits[i] || (t.hour12 && i == 0 && v == 0) {
+			return 0, 0, 0, errors.New("Wrong time!")
+		}
+		values[i] = v
+	}
+	if t.hour12 {
+		eval(t.ampm + " get")
+		values[0] %= 12
+		if result() == "PM" {
+			values[0] += 12
+		}
+	}
+	return values[0], values[1], values[2], nil
+}
+
+// Set hours (0-23), minutes and seconds (ignored without seconds spinbox).
+func (t *TimePicker) SetClock(hours int, minutes int, seconds int) error {
+	if hours < 0 || hours > 23 || minutes < 0 || minutes > 59 || seconds < 0 || seconds > 59 {
+		return errors.New("Wrong time!")
+	}
+	if !t.seconds {
+		seconds = 0
+	}
+	t.hours, t.minutes, t.secs = hours, minutes, seconds
+	if t.id != "" {
+		t.show()
+	}
+	return nil
+}
+
+func (t *TimePicker) show() {
+	h := t.hours
+	if t.hour12 {
+		ampm := "AM"
+		if h >= 12 {
+			ampm = "PM"
+		}
+		eval(t.ampm + " set " + ampm)
+		h %= 12
+		if h == 0 {
+			h = 12
+		}
+	}
+	for i, v := range []int{h, t.minutes, t.secs}[:len(t.spins)] {
+		eval(t.spins[i] + " set " + strconv.Itoa(v/10) + strconv.Itoa(v%10))
+	}
+}
+
+// ======== DateTimePicker =================
+type DateTimePicker struct {
+	Box
+	cal *Calendar
+	tp  *TimePicker
+	loc *time.Location
+}
+
+// Return pointer to new DateTimePicker returning time in location "loc" (time.Local if nil).
+func NewDateTimePicker(loc *time.Location, hour12 bool, seconds bool, flags uint) *DateTimePicker {
+	if loc == nil {
+		loc = time.Local
+	}
+	b := NewBox(flags | Horizontal)
+	d := DateTimePicker{*b, NewCalendar(0), NewTimePicker(hour12, seconds, 0), loc}
+	return &d
+}
+
+func (d *DateTimePicker) create(parentId string) (string, uint) {
+	id, flags := d.widget.create(parentId)
+	widgets[id] = d
+	d.Add(d.cal, d.tp)
+	return id, flags
+}
+
+// Return Calendar of picker (f.e. to set options or limits).
+func (d *DateTimePicker) Calendar() *Calendar {
+	return d.cal
+}
+
+// Return selected date and time in location of picker.
+func (d *DateTimePicker) Value() (time.Time, error) {
+	date := d.cal.Value()
+	if date.IsZero() {
+		return time.Time{}, errors.New("Wrong date!")
+	}
+	h, m, s, err := d.tp.Clock()
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Date(date.Year(), date.Month(), date.Day(), h, m, s, 0, d.loc), nil
+}
+
+// Show "tm" converted to location of picker.
+func (d *DateTimePicker) SetValue(tm time.Time) error {
+	tm = tm.In(d.loc)
+	if err := d.cal.SetValue(time.Date(tm.Year(), tm.Month(), tm.Day(), 0, 0, 0, 0, time.Local)); err != nil {
+		return err
+	}
+	return d.tp.SetClock(tm.Hour(), tm.Minute(), tm.Second())
+}
+
+// Set location, shown date and time are kept.
+func (d *DateTimePicker) SetLocation(loc *time.Location) {
+	if loc == nil {
+		loc = time.Local
+	}
+	d.loc = loc
+}
+
+func (d *DateTimePicker) Location() *time.Location {
+	return d.loc
+}